@@ -6,11 +6,12 @@ import (
 	"os"
 	"strings"
 
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
 func main() {
-	openApiSpecPath := flag.String("openapi", "", "Input file path (.json or .yaml)")
+	openApiSpecPath := flag.String("openapi", "", "Input file path or http(s) url (JSON or YAML), or - to read from stdin")
 	outputPath := flag.String("output", "", "Output file path")
 	schemaMultiplier := flag.Int("multiplier", 100, "Number of times to multiply the schema")
 	flag.Parse()
@@ -19,11 +20,13 @@ func main() {
 		panic("openapi document path is required")
 	}
 
-	reflector, err := openApi31ReflectorFromFile(*openApiSpecPath)
+	document, err := loader.Load(*openApiSpecPath, loader.Options{})
 	if err != nil {
 		panic(err)
 	}
 
+	reflector := document.Reflector
+
 	err = GenerateBigSchema(reflector, *schemaMultiplier)
 	if err != nil {
 		panic(err)
@@ -47,30 +50,6 @@ func main() {
 	}
 }
 
-func openApi31ReflectorFromFile(path string) (*openapi31.Reflector, error) {
-	specBytes, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	reflector := openapi31.NewReflector()
-	if strings.HasSuffix(path, ".json") {
-		err = reflector.Spec.UnmarshalJSON(specBytes)
-		if err != nil {
-			return nil, err
-		}
-	} else if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
-		err = reflector.Spec.UnmarshalYAML(specBytes)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		return nil, fmt.Errorf("Unsupported file format: %s", path)
-	}
-
-	return reflector, nil
-}
-
 func GenerateBigSchema(reflector *openapi31.Reflector, schemaMultiplier int) error {
 	// Generate a huge openapi schema given an input schema
 	newPathMap := map[string]openapi31.PathItem{}
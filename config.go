@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
+	"github.com/RPGillespie6/typed-fetch/pkg/typedfetch"
+	"gopkg.in/yaml.v3"
+)
+
+// Config files that are picked up from the current directory when --config isn't passed
+var configFileNames = []string{"typed-fetch.config.yaml", "typed-fetch.config.yml", "typed-fetch.config.json"}
+
+// Example typed-fetch.config.yaml:
+//
+//	lenient: true # defaults for every generation
+//	generations:
+//	  - openapi: specs/petstore.yaml
+//	    output: src/api/petstore.d.ts
+//	  - openapi: https://internal/api/openapi.yaml
+//	    output: src/api/internal.d.ts
+//	    headers: {Authorization: Bearer ...}
+type Config struct {
+	Generation  `yaml:",inline"`
+	Generations []Generation `yaml:"generations"`
+}
+
+// A single spec -> output generation. Unset fields fall back to the defaults at the top
+// of the config file, and flags passed on the command line override both.
+type Generation struct {
+	OpenApi         string            `yaml:"openapi"`
+	Output          string            `yaml:"output"`
+	OutputDir       string            `yaml:"outputDir"`
+	Timeout         *time.Duration    `yaml:"timeout"`
+	Headers         map[string]string `yaml:"headers"`
+	CacheDir        *string           `yaml:"cacheDir"`
+	AllowStaleCache *bool             `yaml:"allowStaleCache"`
+
+	AllErrors *bool `yaml:"allErrors"`
+	Partial   *bool `yaml:"partial"`
+	Lenient   *bool `yaml:"lenient"`
+	TreeShake *bool `yaml:"treeShake"`
+
+	ExportTypes *bool `yaml:"exportTypes"`
+	Ts          *bool `yaml:"ts"`
+	Namespaces  *bool `yaml:"namespaces"`
+	EnumObjects *bool `yaml:"enumObjects"`
+	HoistInline *bool `yaml:"hoistInlineTypes"`
+
+	// Types by format or vendor extension, with their imports, i.e. mappings: [{format: uuid, type: Uuid, import: ./brands}]
+	Mappings []typedfetch.Mapping `yaml:"mappings"`
+
+	// Opt-in types for formats, i.e. formatTypes: {int64: bigint, dates: brand, brands: true}
+	FormatTypes typedfetch.FormatTypes `yaml:"formatTypes"`
+
+	// Names of the generated types, i.e. naming: {componentSchema: "{name}", casing: preserve}
+	Naming typedfetch.Naming `yaml:"naming"`
+
+	// Layout of the generated TypeScript, i.e. format: {indent: 2, lineWidth: 80, quote: double}
+	Format typedfetch.Format `yaml:"format"`
+
+	// Operations to generate, i.e. include: {tags: [pet]}, exclude: {paths: [/admin/**]}
+	Include typedfetch.OperationFilter `yaml:"include"`
+	Exclude typedfetch.OperationFilter `yaml:"exclude"`
+
+	// Output files and directories of the other generations of the run, which removing stale modules leaves alone
+	otherOutputs []string
+}
+
+func findConfigFile() string {
+	for _, name := range configFileNames {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// Load a YAML or JSON config file. Relative paths in it are relative to the config file, not the working directory.
+func loadConfig(path string) (*Config, error) {
+	configBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(configBytes))
+	decoder.KnownFields(true)
+	err = decoder.Decode(config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	configDir := filepath.Dir(path)
+	config.Generation.resolvePaths(configDir)
+	err = config.Generation.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i := range config.Generations {
+		if config.Generations[i].OpenApi == "" {
+			return nil, fmt.Errorf("%s: generations[%d]: openapi is required", path, i)
+		}
+
+		err = config.Generations[i].validate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: generations[%d]: %w", path, i, err)
+		}
+		config.Generations[i].resolvePaths(configDir)
+	}
+
+	return config, nil
+}
+
+func (g Generation) validate() error {
+	if valueOr(g.EnumObjects, false) && g.Ts != nil && !*g.Ts {
+		return fmt.Errorf("enumObjects requires ts")
+	}
+
+	err := g.Naming.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = g.FormatTypes.Validate()
+	if err != nil {
+		return err
+	}
+
+	for _, mapping := range g.Mappings {
+		err = mapping.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return g.Format.Validate()
+}
+
+func (g *Generation) resolvePaths(dir string) {
+	if g.OpenApi != "" && g.OpenApi != "-" && !loader.IsRemoteLocation(g.OpenApi) && !filepath.IsAbs(g.OpenApi) {
+		g.OpenApi = filepath.Join(dir, g.OpenApi)
+	}
+
+	if g.Output != "" && !filepath.IsAbs(g.Output) {
+		g.Output = filepath.Join(dir, g.Output)
+	}
+
+	if g.OutputDir != "" && !filepath.IsAbs(g.OutputDir) {
+		g.OutputDir = filepath.Join(dir, g.OutputDir)
+	}
+
+	if g.CacheDir != nil && *g.CacheDir != "" && !filepath.IsAbs(*g.CacheDir) {
+		cacheDir := filepath.Join(dir, *g.CacheDir)
+		g.CacheDir = &cacheDir
+	}
+}
+
+func defaultGeneration() Generation {
+	timeout := 30 * time.Second
+	cacheDir := defaultCacheDir()
+	return Generation{
+		Timeout:  &timeout,
+		CacheDir: &cacheDir,
+	}
+}
+
+// Return g with every field that is set in override replaced; maps are merged key by key
+func (g Generation) merge(override Generation) Generation {
+	if override.OpenApi != "" {
+		g.OpenApi = override.OpenApi
+	}
+	if override.Output != "" || override.OutputDir != "" {
+		g.Output = override.Output
+		g.OutputDir = override.OutputDir
+	}
+	if override.Timeout != nil {
+		g.Timeout = override.Timeout
+	}
+	if override.CacheDir != nil {
+		g.CacheDir = override.CacheDir
+	}
+	if override.AllowStaleCache != nil {
+		g.AllowStaleCache = override.AllowStaleCache
+	}
+	if override.AllErrors != nil {
+		g.AllErrors = override.AllErrors
+	}
+	if override.Partial != nil {
+		g.Partial = override.Partial
+	}
+	if override.Lenient != nil {
+		g.Lenient = override.Lenient
+	}
+	if override.TreeShake != nil {
+		g.TreeShake = override.TreeShake
+	}
+	if override.ExportTypes != nil {
+		g.ExportTypes = override.ExportTypes
+	}
+	if override.Ts != nil {
+		g.Ts = override.Ts
+	}
+	if override.Namespaces != nil {
+		g.Namespaces = override.Namespaces
+	}
+	if override.EnumObjects != nil {
+		g.EnumObjects = override.EnumObjects
+	}
+	if override.HoistInline != nil {
+		g.HoistInline = override.HoistInline
+	}
+
+	g.Headers = mergeMaps(g.Headers, override.Headers)
+	if len(override.Mappings) > 0 {
+		// Tried first, so they take precedence over the base mappings
+		g.Mappings = append(append([]typedfetch.Mapping{}, override.Mappings...), g.Mappings...)
+	}
+	g.FormatTypes = mergeFormatTypes(g.FormatTypes, override.FormatTypes)
+	g.Naming = mergeNaming(g.Naming, override.Naming)
+	g.Format = mergeFormat(g.Format, override.Format)
+	g.Include = mergeFilters(g.Include, override.Include)
+	g.Exclude = mergeFilters(g.Exclude, override.Exclude)
+	return g
+}
+
+func (g Generation) loaderOptions() loader.Options {
+	return loader.Options{
+		Timeout:         valueOr(g.Timeout, 0),
+		Headers:         g.Headers,
+		CacheDir:        valueOr(g.CacheDir, ""),
+		AllowStaleCache: valueOr(g.AllowStaleCache, false),
+		OnWarning: func(message string) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", message)
+		},
+	}
+}
+
+func (g Generation) generatorOptions() typedfetch.Options {
+	return typedfetch.Options{
+		CollectErrors:    valueOr(g.AllErrors, false) || valueOr(g.Partial, false),
+		Lenient:          valueOr(g.Lenient, false),
+		TreeShake:        valueOr(g.TreeShake, false),
+		ExportTypes:      valueOr(g.ExportTypes, false),
+		EmitTs:           valueOr(g.Ts, isTsModulePath(g.Output)),
+		Namespaces:       valueOr(g.Namespaces, false),
+		EnumObjects:      valueOr(g.EnumObjects, false),
+		HoistInlineTypes: valueOr(g.HoistInline, false),
+		Mappings:         g.Mappings,
+		FormatTypes:      g.FormatTypes,
+		Naming:           g.Naming,
+		Format:           g.Format,
+		Include:          g.Include,
+		Exclude:          g.Exclude,
+	}
+}
+
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := map[string]string{}
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
+
+// Each field that is set in override replaces the one in base
+func mergeFormatTypes(base, override typedfetch.FormatTypes) typedfetch.FormatTypes {
+	if override.Int64 != "" {
+		base.Int64 = override.Int64
+	}
+	if override.Dates != "" {
+		base.Dates = override.Dates
+	}
+	if override.Brands != nil {
+		base.Brands = override.Brands
+	}
+	return base
+}
+
+// Each template that is set in override replaces the one in base
+func mergeNaming(base, override typedfetch.Naming) typedfetch.Naming {
+	fields := []struct{ base, override *string }{
+		{&base.ComponentSchema, &override.ComponentSchema},
+		{&base.Param, &override.Param},
+		{&base.Body, &override.Body},
+		{&base.Request, &override.Request},
+		{&base.ResponseData, &override.ResponseData},
+		{&base.ResponseError, &override.ResponseError},
+	}
+	for _, field := range fields {
+		if *field.override != "" {
+			*field.base = *field.override
+		}
+	}
+
+	if override.Casing != "" {
+		base.Casing = override.Casing
+	}
+	if override.UseOperationId != nil {
+		base.UseOperationId = override.UseOperationId
+	}
+	return base
+}
+
+// Each field that is set in override replaces the one in base
+func mergeFormat(base, override typedfetch.Format) typedfetch.Format {
+	if override.Indent != 0 {
+		base.Indent = override.Indent
+	}
+	if override.LineWidth != 0 {
+		base.LineWidth = override.LineWidth
+	}
+	if override.Quote != "" {
+		base.Quote = override.Quote
+	}
+	if override.UseTabs != nil {
+		base.UseTabs = override.UseTabs
+	}
+	return base
+}
+
+// Each list that is set in override replaces the one in base
+func mergeFilters(base, override typedfetch.OperationFilter) typedfetch.OperationFilter {
+	if len(override.Tags) > 0 {
+		base.Tags = override.Tags
+	}
+	if len(override.Paths) > 0 {
+		base.Paths = override.Paths
+	}
+	if len(override.Methods) > 0 {
+		base.Methods = override.Methods
+	}
+	if len(override.OperationIds) > 0 {
+		base.OperationIds = override.OperationIds
+	}
+	return base
+}
+
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+// i.e. api.ts, but not api.d.ts
+func isTsModulePath(path string) bool {
+	return strings.HasSuffix(path, ".ts") && !strings.HasSuffix(path, ".d.ts")
+}
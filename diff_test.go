@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// The lines 1 to n, with the given lines replaced (or removed if replaced by "")
+func numberedLines(n int, replace map[int]string) string {
+	lines := &strings.Builder{}
+	for i := 1; i <= n; i++ {
+		line, ok := replace[i]
+		if !ok {
+			line = fmt.Sprint(i)
+		}
+		if line != "" {
+			lines.WriteString(line + "\n")
+		}
+	}
+	return lines.String()
+}
+
+// Expected hunks were checked against diff -u
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "equal", a: "a\nb\n", b: "a\nb\n", want: ""},
+		{name: "empty to content", a: "", b: "a\nb\n", want: "@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{name: "content to empty", a: "a\nb\n", b: "", want: "@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{
+			name: "newline removed",
+			a:    "a\nb\n",
+			b:    "a\nb",
+			want: "@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name: "newline added",
+			a:    "a\nb",
+			b:    "a\nb\nc\n",
+			want: "@@ -1,2 +1,3 @@\n a\n-b\n\\ No newline at end of file\n+b\n+c\n",
+		},
+		{
+			name: "neither ends with a newline",
+			a:    "a",
+			b:    "b",
+			want: "@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name: "context",
+			a:    numberedLines(10, nil),
+			b:    numberedLines(10, map[int]string{5: "x"}),
+			want: "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "context cut off at both ends",
+			a:    numberedLines(20, nil),
+			b:    numberedLines(20, map[int]string{1: "", 20: ""}),
+			want: "@@ -1,4 +1,3 @@\n-1\n 2\n 3\n 4\n@@ -17,4 +16,3 @@\n 17\n 18\n 19\n-20\n",
+		},
+		{
+			name: "changes merged when their context touches",
+			a:    numberedLines(20, nil),
+			b:    numberedLines(20, map[int]string{2: "x", 9: "y"}),
+			want: "@@ -1,12 +1,12 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+y\n 10\n 11\n 12\n",
+		},
+		{
+			name: "changes split when their context doesn't touch",
+			a:    numberedLines(20, nil),
+			b:    numberedLines(20, map[int]string{2: "x", 10: "y"}),
+			want: "@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n 5\n@@ -7,7 +7,7 @@\n 7\n 8\n 9\n-10\n+y\n 11\n 12\n 13\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.want
+			if want != "" {
+				want = "--- a\n+++ b\n" + want
+			}
+
+			got := unifiedDiff("a", "b", test.a, test.b)
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
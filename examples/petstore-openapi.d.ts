@@ -4,8 +4,11 @@
 // Shared types
 
 type RequestInitExtended = {
+    // Local headers -- same as RequestInit but with a Record<string, string> instead of HeadersInit
+    headers?: Record<string, string>;
+
     // If you want the response data to be parse as something other than json (json is default)
-    parseAs?: "json" | "text" | "blob" | "arrayBuffer" | "formData" | "bytes";
+    parseAs?: 'json' | 'text' | 'blob' | 'arrayBuffer' | 'formData' | 'bytes';
 
     // local body serializer -- allows you to customize how the body is serialized before sending
     // normally not needed unless you are using something like XML instead of JSON
@@ -14,7 +17,7 @@ type RequestInitExtended = {
     // local query serializer -- allows you to customize how the query is serialized before sending
     // normally not needed unless you are using some custom array serialization like {foo: [1,2,3,4]} => ?foo=1;2;3;4
     querySerializer?: (query: any) => string;
-}
+};
 
 // Component types
 
@@ -27,26 +30,26 @@ type ComponentSchemaAddress = {
     street?: string;
     /** Example: 94301 */
     zip?: string;
-}
+};
 
 type ComponentSchemaApiResponse = {
     code?: number;
     message?: string;
     type?: string;
-}
+};
 
 type ComponentSchemaCategory = {
     id?: number;
     /** Example: Dogs */
     name?: string;
-}
+};
 
 type ComponentSchemaCustomer = {
     address?: ComponentSchemaAddress[];
     id?: number;
     /** Example: fehguy */
     username?: string;
-}
+};
 
 type ComponentSchemaOrder = {
     complete?: boolean;
@@ -56,7 +59,7 @@ type ComponentSchemaOrder = {
     shipDate?: string;
     /** Order Status; Example: approved */
     status?: 'placed' | 'approved' | 'delivered';
-}
+};
 
 type ComponentSchemaPet = {
     category?: ComponentSchemaCategory;
@@ -67,12 +70,12 @@ type ComponentSchemaPet = {
     /** pet status in the store */
     status?: 'available' | 'pending' | 'sold';
     tags?: ComponentSchemaTag[];
-}
+};
 
 type ComponentSchemaTag = {
     id?: number;
     name?: string;
-}
+};
 
 type ComponentSchemaUser = {
     /** Example: john@email.com */
@@ -90,19 +93,19 @@ type ComponentSchemaUser = {
     userStatus?: number;
     /** Example: theUser */
     username?: string;
-}
+};
 
 // Request/Response types
 
 // PUT /pet
 type BodyPutPet = ComponentSchemaPet;
-type RequestPutPet = Omit<RequestInit, 'body'> & { body: BodyPutPet; } & RequestInitExtended;
+type RequestPutPet = Omit<RequestInit, 'headers' | 'body'> & { body: BodyPutPet; } & RequestInitExtended;
 type ResponseDataPutPet = ComponentSchemaPet;
 type ResponseErrorPutPet = {};
 
 // POST /pet
 type BodyPostPet = ComponentSchemaPet;
-type RequestPostPet = Omit<RequestInit, 'body'> & { body: BodyPostPet; } & RequestInitExtended;
+type RequestPostPet = Omit<RequestInit, 'headers' | 'body'> & { body: BodyPostPet; } & RequestInitExtended;
 type ResponseDataPostPet = ComponentSchemaPet;
 type ResponseErrorPostPet = {};
 
@@ -112,8 +115,8 @@ type ParamGetPetFindByStatus = {
         /** Status values that need to be considered for filter */
         status?: 'available' | 'pending' | 'sold';
     };
-}
-type RequestGetPetFindByStatus = RequestInit & { params?: ParamGetPetFindByStatus; } & RequestInitExtended;
+};
+type RequestGetPetFindByStatus = Omit<RequestInit, 'headers'> & { params?: ParamGetPetFindByStatus; } & RequestInitExtended;
 type ResponseDataGetPetFindByStatus = ComponentSchemaPet[];
 type ResponseErrorGetPetFindByStatus = {};
 
@@ -123,8 +126,8 @@ type ParamGetPetFindByTags = {
         /** Tags to filter by */
         tags?: string[];
     };
-}
-type RequestGetPetFindByTags = RequestInit & { params?: ParamGetPetFindByTags; } & RequestInitExtended;
+};
+type RequestGetPetFindByTags = Omit<RequestInit, 'headers'> & { params?: ParamGetPetFindByTags; } & RequestInitExtended;
 type ResponseDataGetPetFindByTags = ComponentSchemaPet[];
 type ResponseErrorGetPetFindByTags = {};
 
@@ -134,8 +137,8 @@ type ParamGetPetPetId = {
         /** ID of pet to return */
         petId: number;
     };
-}
-type RequestGetPetPetId = RequestInit & { params: ParamGetPetPetId; } & RequestInitExtended;
+};
+type RequestGetPetPetId = Omit<RequestInit, 'headers'> & { params: ParamGetPetPetId; } & RequestInitExtended;
 type ResponseDataGetPetPetId = ComponentSchemaPet;
 type ResponseErrorGetPetPetId = {};
 
@@ -151,8 +154,8 @@ type ParamPostPetPetId = {
         /** Status of pet that needs to be updated */
         status?: string;
     };
-}
-type RequestPostPetPetId = RequestInit & { params: ParamPostPetPetId; } & RequestInitExtended;
+};
+type RequestPostPetPetId = Omit<RequestInit, 'headers'> & { params: ParamPostPetPetId; } & RequestInitExtended;
 type ResponseDataPostPetPetId = {};
 type ResponseErrorPostPetPetId = {};
 
@@ -165,8 +168,8 @@ type ParamDeletePetPetId = {
         /** Pet id to delete */
         petId: number;
     };
-}
-type RequestDeletePetPetId = RequestInit & { params: ParamDeletePetPetId; } & RequestInitExtended;
+};
+type RequestDeletePetPetId = Omit<RequestInit, 'headers'> & { params: ParamDeletePetPetId; } & RequestInitExtended;
 type ResponseDataDeletePetPetId = {};
 type ResponseErrorDeletePetPetId = {};
 
@@ -180,14 +183,14 @@ type ParamPostPetPetIdUploadImage = {
         /** Additional Metadata */
         additionalMetadata?: string;
     };
-}
-type BodyPostPetPetIdUploadImage = string;
-type RequestPostPetPetIdUploadImage = Omit<RequestInit, 'body'> & { params: ParamPostPetPetIdUploadImage; body?: BodyPostPetPetIdUploadImage; } & RequestInitExtended;
+};
+type BodyPostPetPetIdUploadImage = ArrayBuffer | Blob | File;
+type RequestPostPetPetIdUploadImage = Omit<RequestInit, 'headers' | 'body'> & { params: ParamPostPetPetIdUploadImage; body?: BodyPostPetPetIdUploadImage; } & RequestInitExtended;
 type ResponseDataPostPetPetIdUploadImage = ComponentSchemaApiResponse;
 type ResponseErrorPostPetPetIdUploadImage = {};
 
 // GET /store/inventory
-type RequestGetStoreInventory = RequestInit & RequestInitExtended;
+type RequestGetStoreInventory = Omit<RequestInit, 'headers'> & RequestInitExtended;
 type ResponseDataGetStoreInventory = {
     [key: string]: number;
 };
@@ -195,7 +198,7 @@ type ResponseErrorGetStoreInventory = {};
 
 // POST /store/order
 type BodyPostStoreOrder = ComponentSchemaOrder;
-type RequestPostStoreOrder = Omit<RequestInit, 'body'> & { body?: BodyPostStoreOrder; } & RequestInitExtended;
+type RequestPostStoreOrder = Omit<RequestInit, 'headers' | 'body'> & { body?: BodyPostStoreOrder; } & RequestInitExtended;
 type ResponseDataPostStoreOrder = ComponentSchemaOrder;
 type ResponseErrorPostStoreOrder = {};
 
@@ -205,8 +208,8 @@ type ParamGetStoreOrderOrderId = {
         /** ID of order that needs to be fetched */
         orderId: number;
     };
-}
-type RequestGetStoreOrderOrderId = RequestInit & { params: ParamGetStoreOrderOrderId; } & RequestInitExtended;
+};
+type RequestGetStoreOrderOrderId = Omit<RequestInit, 'headers'> & { params: ParamGetStoreOrderOrderId; } & RequestInitExtended;
 type ResponseDataGetStoreOrderOrderId = ComponentSchemaOrder;
 type ResponseErrorGetStoreOrderOrderId = {};
 
@@ -216,20 +219,20 @@ type ParamDeleteStoreOrderOrderId = {
         /** ID of the order that needs to be deleted */
         orderId: number;
     };
-}
-type RequestDeleteStoreOrderOrderId = RequestInit & { params: ParamDeleteStoreOrderOrderId; } & RequestInitExtended;
+};
+type RequestDeleteStoreOrderOrderId = Omit<RequestInit, 'headers'> & { params: ParamDeleteStoreOrderOrderId; } & RequestInitExtended;
 type ResponseDataDeleteStoreOrderOrderId = {};
 type ResponseErrorDeleteStoreOrderOrderId = {};
 
 // POST /user
 type BodyPostUser = ComponentSchemaUser;
-type RequestPostUser = Omit<RequestInit, 'body'> & { body?: BodyPostUser; } & RequestInitExtended;
+type RequestPostUser = Omit<RequestInit, 'headers' | 'body'> & { body?: BodyPostUser; } & RequestInitExtended;
 type ResponseDataPostUser = ComponentSchemaUser;
 type ResponseErrorPostUser = ComponentSchemaUser;
 
 // POST /user/createWithList
 type BodyPostUserCreateWithList = ComponentSchemaUser[];
-type RequestPostUserCreateWithList = Omit<RequestInit, 'body'> & { body?: BodyPostUserCreateWithList; } & RequestInitExtended;
+type RequestPostUserCreateWithList = Omit<RequestInit, 'headers' | 'body'> & { body?: BodyPostUserCreateWithList; } & RequestInitExtended;
 type ResponseDataPostUserCreateWithList = ComponentSchemaUser;
 type ResponseErrorPostUserCreateWithList = {};
 
@@ -241,13 +244,13 @@ type ParamGetUserLogin = {
         /** The password for login in clear text */
         password?: string;
     };
-}
-type RequestGetUserLogin = RequestInit & { params?: ParamGetUserLogin; } & RequestInitExtended;
+};
+type RequestGetUserLogin = Omit<RequestInit, 'headers'> & { params?: ParamGetUserLogin; } & RequestInitExtended;
 type ResponseDataGetUserLogin = string;
 type ResponseErrorGetUserLogin = {};
 
 // GET /user/logout
-type RequestGetUserLogout = RequestInit & RequestInitExtended;
+type RequestGetUserLogout = Omit<RequestInit, 'headers'> & RequestInitExtended;
 type ResponseDataGetUserLogout = {};
 type ResponseErrorGetUserLogout = {};
 
@@ -257,8 +260,8 @@ type ParamGetUserUsername = {
         /** The name that needs to be fetched. Use user1 for testing.  */
         username: string;
     };
-}
-type RequestGetUserUsername = RequestInit & { params: ParamGetUserUsername; } & RequestInitExtended;
+};
+type RequestGetUserUsername = Omit<RequestInit, 'headers'> & { params: ParamGetUserUsername; } & RequestInitExtended;
 type ResponseDataGetUserUsername = ComponentSchemaUser;
 type ResponseErrorGetUserUsername = {};
 
@@ -268,9 +271,9 @@ type ParamPutUserUsername = {
         /** name that needs to be updated */
         username: string;
     };
-}
+};
 type BodyPutUserUsername = ComponentSchemaUser;
-type RequestPutUserUsername = Omit<RequestInit, 'body'> & { params: ParamPutUserUsername; body?: BodyPutUserUsername; } & RequestInitExtended;
+type RequestPutUserUsername = Omit<RequestInit, 'headers' | 'body'> & { params: ParamPutUserUsername; body?: BodyPutUserUsername; } & RequestInitExtended;
 type ResponseDataPutUserUsername = {};
 type ResponseErrorPutUserUsername = {};
 
@@ -280,8 +283,8 @@ type ParamDeleteUserUsername = {
         /** The name that needs to be deleted */
         username: string;
     };
-}
-type RequestDeleteUserUsername = RequestInit & { params: ParamDeleteUserUsername; } & RequestInitExtended;
+};
+type RequestDeleteUserUsername = Omit<RequestInit, 'headers'> & { params: ParamDeleteUserUsername; } & RequestInitExtended;
 type ResponseDataDeleteUserUsername = {};
 type ResponseErrorDeleteUserUsername = {};
 
@@ -294,37 +297,84 @@ type FetchResponse<D, E> = DataResponse<D> | ErrorResponse<E>;
 // Generics Type Lookups
 // These are lookup tables for each method type (GET, POST, etc) to match the url to its payload
 
-type GetTypesLookup = {
-    "/pet/findByStatus": { init?: RequestGetPetFindByStatus, response: FetchResponse<ResponseDataGetPetFindByStatus, ResponseErrorGetPetFindByStatus> },
-    "/pet/findByTags": { init?: RequestGetPetFindByTags, response: FetchResponse<ResponseDataGetPetFindByTags, ResponseErrorGetPetFindByTags> },
-    "/pet/{petId}": { init: RequestGetPetPetId, response: FetchResponse<ResponseDataGetPetPetId, ResponseErrorGetPetPetId> },
-    "/store/inventory": { init?: RequestGetStoreInventory, response: FetchResponse<ResponseDataGetStoreInventory, ResponseErrorGetStoreInventory> },
-    "/store/order/{orderId}": { init: RequestGetStoreOrderOrderId, response: FetchResponse<ResponseDataGetStoreOrderOrderId, ResponseErrorGetStoreOrderOrderId> },
-    "/user/login": { init?: RequestGetUserLogin, response: FetchResponse<ResponseDataGetUserLogin, ResponseErrorGetUserLogin> },
-    "/user/logout": { init?: RequestGetUserLogout, response: FetchResponse<ResponseDataGetUserLogout, ResponseErrorGetUserLogout> },
-    "/user/{username}": { init: RequestGetUserUsername, response: FetchResponse<ResponseDataGetUserUsername, ResponseErrorGetUserUsername> }
-};
-
 type DeleteTypesLookup = {
-    "/pet/{petId}": { init: RequestDeletePetPetId, response: FetchResponse<ResponseDataDeletePetPetId, ResponseErrorDeletePetPetId> },
-    "/store/order/{orderId}": { init: RequestDeleteStoreOrderOrderId, response: FetchResponse<ResponseDataDeleteStoreOrderOrderId, ResponseErrorDeleteStoreOrderOrderId> },
-    "/user/{username}": { init: RequestDeleteUserUsername, response: FetchResponse<ResponseDataDeleteUserUsername, ResponseErrorDeleteUserUsername> }
+    '/pet/{petId}': {
+        init: RequestDeletePetPetId;
+        response: FetchResponse<ResponseDataDeletePetPetId, ResponseErrorDeletePetPetId>;
+    };
+    '/store/order/{orderId}': {
+        init: RequestDeleteStoreOrderOrderId;
+        response: FetchResponse<ResponseDataDeleteStoreOrderOrderId, ResponseErrorDeleteStoreOrderOrderId>;
+    };
+    '/user/{username}': {
+        init: RequestDeleteUserUsername;
+        response: FetchResponse<ResponseDataDeleteUserUsername, ResponseErrorDeleteUserUsername>;
+    };
 };
 
-type PutTypesLookup = {
-    "/pet": { init: RequestPutPet, response: FetchResponse<ResponseDataPutPet, ResponseErrorPutPet> },
-    "/user/{username}": { init: RequestPutUserUsername, response: FetchResponse<ResponseDataPutUserUsername, ResponseErrorPutUserUsername> }
+type GetTypesLookup = {
+    '/pet/findByStatus': {
+        init?: RequestGetPetFindByStatus;
+        response: FetchResponse<ResponseDataGetPetFindByStatus, ResponseErrorGetPetFindByStatus>;
+    };
+    '/pet/findByTags': {
+        init?: RequestGetPetFindByTags;
+        response: FetchResponse<ResponseDataGetPetFindByTags, ResponseErrorGetPetFindByTags>;
+    };
+    '/pet/{petId}': {
+        init: RequestGetPetPetId;
+        response: FetchResponse<ResponseDataGetPetPetId, ResponseErrorGetPetPetId>;
+    };
+    '/store/inventory': {
+        init?: RequestGetStoreInventory;
+        response: FetchResponse<ResponseDataGetStoreInventory, ResponseErrorGetStoreInventory>;
+    };
+    '/store/order/{orderId}': {
+        init: RequestGetStoreOrderOrderId;
+        response: FetchResponse<ResponseDataGetStoreOrderOrderId, ResponseErrorGetStoreOrderOrderId>;
+    };
+    '/user/login': {
+        init?: RequestGetUserLogin;
+        response: FetchResponse<ResponseDataGetUserLogin, ResponseErrorGetUserLogin>;
+    };
+    '/user/logout': {
+        init?: RequestGetUserLogout;
+        response: FetchResponse<ResponseDataGetUserLogout, ResponseErrorGetUserLogout>;
+    };
+    '/user/{username}': {
+        init: RequestGetUserUsername;
+        response: FetchResponse<ResponseDataGetUserUsername, ResponseErrorGetUserUsername>;
+    };
 };
 
 type PostTypesLookup = {
-    "/pet": { init: RequestPostPet, response: FetchResponse<ResponseDataPostPet, ResponseErrorPostPet> },
-    "/pet/{petId}": { init: RequestPostPetPetId, response: FetchResponse<ResponseDataPostPetPetId, ResponseErrorPostPetPetId> },
-    "/pet/{petId}/uploadImage": { init: RequestPostPetPetIdUploadImage, response: FetchResponse<ResponseDataPostPetPetIdUploadImage, ResponseErrorPostPetPetIdUploadImage> },
-    "/store/order": { init?: RequestPostStoreOrder, response: FetchResponse<ResponseDataPostStoreOrder, ResponseErrorPostStoreOrder> },
-    "/user": { init?: RequestPostUser, response: FetchResponse<ResponseDataPostUser, ResponseErrorPostUser> },
-    "/user/createWithList": { init?: RequestPostUserCreateWithList, response: FetchResponse<ResponseDataPostUserCreateWithList, ResponseErrorPostUserCreateWithList> }
+    '/pet': { init: RequestPostPet; response: FetchResponse<ResponseDataPostPet, ResponseErrorPostPet>; };
+    '/pet/{petId}': {
+        init: RequestPostPetPetId;
+        response: FetchResponse<ResponseDataPostPetPetId, ResponseErrorPostPetPetId>;
+    };
+    '/pet/{petId}/uploadImage': {
+        init: RequestPostPetPetIdUploadImage;
+        response: FetchResponse<ResponseDataPostPetPetIdUploadImage, ResponseErrorPostPetPetIdUploadImage>;
+    };
+    '/store/order': {
+        init?: RequestPostStoreOrder;
+        response: FetchResponse<ResponseDataPostStoreOrder, ResponseErrorPostStoreOrder>;
+    };
+    '/user': { init?: RequestPostUser; response: FetchResponse<ResponseDataPostUser, ResponseErrorPostUser>; };
+    '/user/createWithList': {
+        init?: RequestPostUserCreateWithList;
+        response: FetchResponse<ResponseDataPostUserCreateWithList, ResponseErrorPostUserCreateWithList>;
+    };
 };
 
+type PutTypesLookup = {
+    '/pet': { init: RequestPutPet; response: FetchResponse<ResponseDataPutPet, ResponseErrorPutPet>; };
+    '/user/{username}': {
+        init: RequestPutUserUsername;
+        response: FetchResponse<ResponseDataPutUserUsername, ResponseErrorPutUserUsername>;
+    };
+};
 
 /* 
     We could just generate a bunch of overloaded functions in the interface like:
@@ -364,8 +414,8 @@ type ClientMethod<Lookup extends Record<string, any>> = <Url extends keyof Looku
 // end evil TypeScript magic
 
 export interface Client {
-    GET: ClientMethod<GetTypesLookup>;
     DELETE: ClientMethod<DeleteTypesLookup>;
-    PUT: ClientMethod<PutTypesLookup>;
+    GET: ClientMethod<GetTypesLookup>;
     POST: ClientMethod<PostTypesLookup>;
+    PUT: ClientMethod<PutTypesLookup>;
 }
\ No newline at end of file
@@ -2,7 +2,10 @@ module github.com/RPGillespie6/typed-fetch
 
 go 1.21.6
 
-require github.com/swaggest/openapi-go v0.2.53
+require (
+	github.com/swaggest/openapi-go v0.2.53
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/swaggest/jsonschema-go v0.3.72 // indirect
@@ -1,65 +1,443 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
 	"github.com/RPGillespie6/typed-fetch/pkg/typedfetch"
-	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const (
+	exitOk          = 0
+	exitInvalidSpec = 1
+	exitUsage       = 2
+	exitIoError     = 3
+	exitOutOfDate   = 4
 )
 
 func main() {
-	openApiSpecPath := flag.String("openapi", "", "Input file path (.json or .yaml)")
+	os.Exit(run())
+}
+
+func run() int {
+	configPath := flag.String("config", "", "Config file listing the generations to run (default: typed-fetch.config.(yaml|yml|json) in the current directory, if any)")
+	openApiSpecPath := flag.String("openapi", "", "Input file path or http(s) url (JSON or YAML), or - to read from stdin")
 	outputPath := flag.String("output", "", "Output file path")
+	outputDir := flag.String("output-dir", "", "Split the output into modules (components, operations by tag, client) in this directory")
+	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for fetching remote documents")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "Directory to cache remote documents in (empty to disable)")
+	allowStaleCache := flag.Bool("allow-stale-cache", false, "Use the cached copy of a remote document even when the server rejects the request (4xx)")
+	headers := headerFlags{}
+	flag.Var(headers, "header", "Extra header for remote requests to the spec's host, i.e. \"Authorization: Bearer ...\" (repeatable)")
+	allErrors := flag.Bool("all-errors", false, "Report every invalid or unsupported construct instead of stopping at the first")
+	partial := flag.Bool("partial", false, "Write the output generated for everything that succeeded even if there are errors (implies --all-errors)")
+	lenient := flag.Bool("lenient", false, "Generate unknown (with a warning) for schemas that can't be translated instead of failing")
+	treeShake := flag.Bool("tree-shake", false, "Leave out component types that no operation references")
+	exportTypes := flag.Bool("export-types", false, "Export every generated type, not just Client")
+	ts := flag.Bool("ts", false, "Generate .ts modules instead of .d.ts declarations, implies --export-types (default: true if --output ends with .ts but not .d.ts)")
+	namespaces := flag.Bool("namespaces", false, "Also generate namespaced aliases, i.e. Components.Schemas.Pet and Operations.GetPetById.Body")
+	enumObjects := flag.Bool("enum-objects", false, "Also generate a const object of the values of each enum, hoisting inline enums to named types (requires --ts)")
+	hoistInline := flag.Bool("hoist-inline", false, "Hoist inline object and enum schemas to named types after their location, i.e. BodyPostPetCategory")
+	include := typedfetch.OperationFilter{}
+	exclude := typedfetch.OperationFilter{}
+	flag.Var((*listFlags)(&include.Tags), "tag", "Only generate operations with this tag (repeatable or comma separated)")
+	flag.Var((*listFlags)(&include.Paths), "path", "Only generate operations whose path matches this glob, i.e. /store/** (repeatable or comma separated)")
+	flag.Var((*listFlags)(&include.Methods), "method", "Only generate operations with this method (repeatable or comma separated)")
+	flag.Var((*listFlags)(&include.OperationIds), "operation-id", "Only generate the operation with this operationId (repeatable or comma separated)")
+	flag.Var((*listFlags)(&exclude.Tags), "exclude-tag", "Don't generate operations with this tag (repeatable or comma separated)")
+	flag.Var((*listFlags)(&exclude.Paths), "exclude-path", "Don't generate operations whose path matches this glob (repeatable or comma separated)")
+	flag.Var((*listFlags)(&exclude.Methods), "exclude-method", "Don't generate operations with this method (repeatable or comma separated)")
+	flag.Var((*listFlags)(&exclude.OperationIds), "exclude-operation-id", "Don't generate the operation with this operationId (repeatable or comma separated)")
+	check := flag.Bool("check", false, "Don't write anything, exit with a diff if the existing output is out of date")
+	watchSources := flag.Bool("watch", false, "Keep running and regenerate whenever the spec or a file it references changes")
 	flag.Parse()
 
-	if *openApiSpecPath == "" {
-		panic("openapi document path is required")
+	// Only flags passed explicitly override the config file
+	overrides := Generation{Include: include, Exclude: exclude}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "openapi":
+			overrides.OpenApi = *openApiSpecPath
+		case "output":
+			overrides.Output = *outputPath
+		case "output-dir":
+			overrides.OutputDir = *outputDir
+		case "timeout":
+			overrides.Timeout = timeout
+		case "cache-dir":
+			overrides.CacheDir = cacheDir
+		case "allow-stale-cache":
+			overrides.AllowStaleCache = allowStaleCache
+		case "header":
+			overrides.Headers = headers
+		case "all-errors":
+			overrides.AllErrors = allErrors
+		case "partial":
+			overrides.Partial = partial
+		case "lenient":
+			overrides.Lenient = lenient
+		case "tree-shake":
+			overrides.TreeShake = treeShake
+		case "export-types":
+			overrides.ExportTypes = exportTypes
+		case "ts":
+			overrides.Ts = ts
+		case "namespaces":
+			overrides.Namespaces = namespaces
+		case "enum-objects":
+			overrides.EnumObjects = enumObjects
+		case "hoist-inline":
+			overrides.HoistInline = hoistInline
+		}
+	})
+
+	config := &Config{}
+	if *configPath == "" && *openApiSpecPath == "" {
+		*configPath = findConfigFile()
+	}
+	if *configPath != "" {
+		var err error
+		config, err = loadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			return exitUsage
+		}
+	}
+
+	// A spec passed on the command line replaces the configured generations
+	generations := config.Generations
+	if *openApiSpecPath != "" {
+		generations = []Generation{{}}
+	}
+
+	if len(generations) == 0 {
+		fmt.Fprintln(os.Stderr, "error: --openapi or a config file with generations is required")
+		flag.Usage()
+		return exitUsage
+	}
+
+	// Every generation would overwrite the same output
+	if len(generations) > 1 && (overrides.Output != "" || overrides.OutputDir != "") {
+		fmt.Fprintln(os.Stderr, "error: --output and --output-dir can only override the output of a config file with a single generation, pass --openapi too")
+		return exitUsage
 	}
 
-	reflector, err := openApi31ReflectorFromFile(*openApiSpecPath)
+	for i, generation := range generations {
+		generations[i] = defaultGeneration().merge(config.Generation).merge(generation).merge(overrides)
+	}
+	for i := range generations {
+		for j, other := range generations {
+			if i != j {
+				generations[i].otherOutputs = append(generations[i].otherOutputs, other.Output, other.OutputDir)
+			}
+		}
+	}
+
+	if *watchSources {
+		if *check {
+			fmt.Fprintln(os.Stderr, "error: --watch and --check can't be combined")
+			return exitUsage
+		}
+		return watch(generations)
+	}
+
+	exitCode := exitOk
+	for _, generation := range generations {
+		exitCode = max(exitCode, runGeneration(generation, *check))
+	}
+
+	return exitCode
+}
+
+// Generate the output of a single spec and return the exit code
+func runGeneration(generation Generation, check bool) int {
+	err := validateGeneration(generation, check)
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return exitUsage
 	}
 
-	generatedOutput, err := typedfetch.GenerateTypedFetch(reflector)
+	document, err := loader.Load(generation.OpenApi, generation.loaderOptions())
 	if err != nil {
-		panic(err)
+		return reportError(err, nil)
+	}
+
+	return generateOutput(generation, document, check)
+}
+
+// Check the options of a generation that can't be combined, before anything is loaded or written
+func validateGeneration(generation Generation, check bool) error {
+	if check && generation.Output == "" && generation.OutputDir == "" {
+		return fmt.Errorf("%s: --check requires an output file or directory", generation.OpenApi)
+	}
+
+	if generation.Output != "" && generation.OutputDir != "" {
+		return fmt.Errorf("%s: --output and --output-dir can't be combined", generation.OpenApi)
+	}
+
+	options := generation.generatorOptions()
+	if options.EnumObjects && !options.EmitTs {
+		return fmt.Errorf("%s: --enum-objects requires --ts (or an --output ending with .ts), a .d.ts can't have runtime values", generation.OpenApi)
+	}
+
+	return nil
+}
+
+// Generate the output of a loaded spec and write (or check) it; return the exit code
+func generateOutput(generation Generation, document *loader.Document, check bool) int {
+	options := generation.generatorOptions()
+	options.OnWarning = func(warning *typedfetch.SpecError) {
+		reportSpecError("warning", warning, document)
+	}
+
+	// Output file path -> contents, with "" for stdout
+	files := map[string]string{}
+	generator, generateErr := typedfetch.NewGenerator(document.Reflector, options)
+	if generateErr != nil {
+		return reportError(generateErr, document)
+	}
+
+	if generation.OutputDir != "" {
+		var modules map[string]string
+		modules, generateErr = generator.GenerateFiles()
+		for name, contents := range modules {
+			files[filepath.Join(generation.OutputDir, filepath.FromSlash(name))] = contents
+		}
+	} else {
+		var generatedOutput string
+		generatedOutput, generateErr = generator.Generate()
+		files[generation.Output] = generatedOutput
+	}
+
+	partial := valueOr(generation.Partial, false)
+	if generateErr != nil && !partial {
+		return reportError(generateErr, document)
 	}
 
 	// Generate typed fetch
-	if *outputPath != "" {
-		err = os.WriteFile(*outputPath, []byte(generatedOutput), 0644)
+	exitCode := exitOk
+	for _, outputPath := range sortedKeys(files) {
+		if check {
+			exitCode = max(exitCode, checkOutput(outputPath, files[outputPath]))
+			continue
+		}
+
+		if outputPath == "" {
+			fmt.Println(files[outputPath])
+			continue
+		}
+
+		err := os.MkdirAll(filepath.Dir(outputPath), 0755)
+		if err == nil {
+			err = os.WriteFile(outputPath, []byte(files[outputPath]), 0644)
+		}
 		if err != nil {
-			panic(err)
+			return reportError(err, document)
 		}
-	} else {
-		fmt.Println(generatedOutput)
 	}
+
+	if generation.OutputDir != "" {
+		exitCode = max(exitCode, removeStaleFiles(generation, files, check, document))
+	}
+
+	if exitCode != exitOk {
+		return exitCode
+	}
+
+	if generateErr != nil {
+		return reportError(generateErr, document)
+	}
+
+	return exitOk
 }
 
-func openApi31ReflectorFromFile(path string) (*openapi31.Reflector, error) {
-	specBytes, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// Compare the generated output with the existing output file, printing a unified diff if it's out of date
+func checkOutput(outputPath, generatedOutput string) int {
+	existingOutput, err := os.ReadFile(outputPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return reportError(err, nil)
 	}
 
-	reflector := openapi31.NewReflector()
-	if strings.HasSuffix(path, ".json") {
-		err = reflector.Spec.UnmarshalJSON(specBytes)
+	diff := unifiedDiff(outputPath, outputPath+" (generated)", string(existingOutput), generatedOutput)
+	if diff != "" {
+		fmt.Print(diff)
+		fmt.Fprintf(os.Stderr, "error: %s is out of date, rerun typed-fetch without --check\n", outputPath)
+		return exitOutOfDate
+	}
+
+	return exitOk
+}
+
+// Remove the modules a previous run generated in the output directory that this one didn't, i.e. the module of a
+// tag that's gone. With check, report them instead. Only the layout of GenerateTypedFetchFiles is looked at, and the
+// outputs of the other generations of the run are left alone.
+func removeStaleFiles(generation Generation, files map[string]string, check bool, document *loader.Document) int {
+	stale := []string{}
+	for _, dir := range []string{"", "operations"} {
+		entries, err := os.ReadDir(filepath.Join(generation.OutputDir, dir))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
 		if err != nil {
-			return nil, err
+			return reportError(err, document)
+		}
+
+		for _, entry := range entries {
+			name := path.Join(dir, entry.Name())
+			outputPath := filepath.Join(generation.OutputDir, filepath.FromSlash(name))
+			if _, ok := files[outputPath]; ok || entry.IsDir() || !typedfetch.IsModuleFile(name) || isOtherOutput(generation, outputPath) {
+				continue
+			}
+
+			// Leave alone anything typed-fetch didn't generate
+			contents, err := os.ReadFile(outputPath)
+			if err != nil {
+				return reportError(err, document)
+			}
+			if strings.HasPrefix(string(contents), typedfetch.GeneratedComment) {
+				stale = append(stale, outputPath)
+			}
 		}
-	} else if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
-		err = reflector.Spec.UnmarshalYAML(specBytes)
+	}
+
+	for _, stalePath := range stale {
+		if check {
+			fmt.Fprintf(os.Stderr, "error: %s is no longer generated, rerun typed-fetch without --check\n", stalePath)
+			continue
+		}
+
+		err := os.Remove(stalePath)
 		if err != nil {
-			return nil, err
+			return reportError(err, document)
 		}
-	} else {
-		return nil, fmt.Errorf("Unsupported file format: %s", path)
 	}
 
-	return reflector, nil
+	if check && len(stale) > 0 {
+		return exitOutOfDate
+	}
+	return exitOk
+}
+
+// Whether outputPath is, or is within, the output of another generation of the run
+func isOtherOutput(generation Generation, outputPath string) bool {
+	for _, other := range generation.otherOutputs {
+		if other == "" {
+			continue
+		}
+
+		rel, err := filepath.Rel(other, outputPath)
+		if err == nil && (rel == "." || !strings.HasPrefix(rel, "..")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Print err to stderr (prefixed with file:line:col when it points into the document) and return the exit code
+func reportError(err error, document *loader.Document) int {
+	var specErrs typedfetch.SpecErrors
+	if errors.As(err, &specErrs) {
+		for _, specErr := range specErrs {
+			reportSpecError("error", specErr, document)
+		}
+		if len(specErrs) > 1 {
+			fmt.Fprintf(os.Stderr, "%d errors\n", len(specErrs))
+		}
+		return exitInvalidSpec
+	}
+
+	var specErr *typedfetch.SpecError
+	if errors.As(err, &specErr) {
+		reportSpecError("error", specErr, document)
+		return exitInvalidSpec
+	}
+
+	fmt.Fprintf(os.Stderr, "error: %s\n", err)
+
+	var pathErr *fs.PathError
+	var urlErr *url.Error
+	if errors.As(err, &pathErr) || errors.As(err, &urlErr) {
+		return exitIoError
+	}
+
+	return exitInvalidSpec
+}
+
+// severity is "error" or "warning"
+func reportSpecError(severity string, specErr *typedfetch.SpecError, document *loader.Document) {
+	if document != nil {
+		if position, ok := document.Position(specErr.Pointer); ok {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", position, severity, specErr)
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %s\n", severity, specErr)
+}
+
+// Implements flag.Value so --header can be passed multiple times
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	headers := []string{}
+	for name, value := range h {
+		headers = append(headers, fmt.Sprintf("%s: %s", name, value))
+	}
+	return strings.Join(headers, ", ")
+}
+
+func (h headerFlags) Set(header string) error {
+	name, value, ok := strings.Cut(header, ":")
+	if !ok {
+		return fmt.Errorf("expected \"Name: value\", got %q", header)
+	}
+
+	h[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	return nil
+}
+
+// Implements flag.Value for flags that can be repeated and/or comma separated, i.e. --tag pet,store --tag user
+type listFlags []string
+
+func (l *listFlags) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlags) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func defaultCacheDir() string {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(userCacheDir, "typed-fetch")
 }
@@ -0,0 +1,379 @@
+package loader
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The generator only understands local references (#/components/...), so every external
+// reference (./common.yaml#/components/schemas/Foo, https://host/spec.yaml#/...) is copied
+// into the root document's components and rewritten to point at the copy.
+type bundler struct {
+	options Options
+	root    map[string]any
+
+	// Origin (scheme://host) of the root document, the only one options.Headers are sent to; empty if it's local
+	rootOrigin string
+
+	// Parsed external documents by absolute location
+	documents map[string]map[string]any
+
+	// Absolute external reference -> local reference, so each target is only imported once
+	imported map[string]string
+
+	// Positions of every node in each external document by location, and where each imported component came from
+	positions map[string]map[string]Position
+	origins   map[string]origin
+}
+
+type origin struct {
+	location string
+	pointer  string
+}
+
+func bundleExternalRefs(root map[string]any, location string, options Options) (*bundler, error) {
+	b := &bundler{
+		options:    options,
+		root:       root,
+		rootOrigin: remoteOrigin(location),
+		documents:  map[string]map[string]any{},
+		imported:   map[string]string{},
+		positions:  map[string]map[string]Position{},
+		origins:    map[string]origin{},
+	}
+
+	err := b.rewriteRefs(root, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Local files read while bundling the root document at location, including the root document itself
+func (b *bundler) sources(location string) []string {
+	sources := []string{}
+	if location != "-" && !IsRemoteLocation(location) {
+		sources = append(sources, location)
+	}
+
+	for documentLocation := range b.documents {
+		if !IsRemoteLocation(documentLocation) {
+			sources = append(sources, documentLocation)
+		}
+	}
+
+	sort.Strings(sources)
+	return sources
+}
+
+func (b *bundler) rewriteRefs(node any, base string) error {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			ref, ok := value.(string)
+			if key != "$ref" || !ok {
+				err := b.rewriteRefs(value, base)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if strings.HasPrefix(ref, "#") {
+				continue
+			}
+
+			localRef, err := b.importRef(resolveLocation(base, ref))
+			if err != nil {
+				return fmt.Errorf("%s: %w", ref, err)
+			}
+			v[key] = localRef
+		}
+	case []any:
+		for _, value := range v {
+			err := b.rewriteRefs(value, base)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *bundler) importRef(absoluteRef string) (string, error) {
+	// common.yaml and common.yaml# both refer to the whole document
+	location, fragment, _ := strings.Cut(absoluteRef, "#")
+	absoluteRef = location + "#" + fragment
+	if localRef, ok := b.imported[absoluteRef]; ok {
+		return localRef, nil
+	}
+
+	doc, err := b.loadDocument(location)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := resolvePointer(doc, fragment)
+	if err != nil {
+		return "", err
+	}
+
+	// Register the local ref before descending so that recursive schemas terminate
+	container, name := b.componentSlot(location, fragment)
+	name = uniqueComponentName(container, name)
+	container[name] = nil
+	localRef := fmt.Sprintf("%s%s", b.componentPrefix(fragment), escapePointerToken(name))
+	b.imported[absoluteRef] = localRef
+	b.origins[strings.TrimPrefix(localRef, "#")] = origin{location: location, pointer: canonicalPointer(fragment)}
+
+	// Local refs inside the external document are relative to that document
+	copied := deepCopy(target)
+	absolutizeLocalRefs(copied, location)
+	err = b.rewriteRefs(copied, location)
+	if err != nil {
+		return "", err
+	}
+
+	container[name] = copied
+	return localRef, nil
+}
+
+func (b *bundler) loadDocument(location string) (map[string]any, error) {
+	if doc, ok := b.documents[location]; ok {
+		return doc, nil
+	}
+
+	// Headers usually carry credentials for the root document's host, which other hosts must not see
+	options := b.options
+	if b.rootOrigin == "" || remoteOrigin(location) != b.rootOrigin {
+		options.Headers = nil
+	}
+
+	specBytes, err := readLocation(location, options)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseDocument(specBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+
+	b.documents[location] = doc
+	b.positions[location] = buildPositions(location, specBytes)
+	return doc, nil
+}
+
+// Imported components keep their kind (#/components/parameters/... stays a parameter);
+// anything else is assumed to be a schema, named after the last pointer token or the file name
+func (b *bundler) componentSlot(location, fragment string) (map[string]any, string) {
+	name := strings.TrimSuffix(path.Base(location), path.Ext(location))
+	tokens := pointerTokens(fragment)
+	if len(tokens) > 0 {
+		name = tokens[len(tokens)-1]
+	}
+
+	container := b.root
+	for _, key := range b.componentPath(fragment) {
+		container = ensureMap(container, key)
+	}
+
+	return container, name
+}
+
+func (b *bundler) componentPrefix(fragment string) string {
+	return "#/" + strings.Join(b.componentPath(fragment), "/") + "/"
+}
+
+func (b *bundler) componentPath(fragment string) []string {
+	tokens := pointerTokens(fragment)
+
+	if _, isSwagger := b.root["swagger"]; isSwagger {
+		if len(tokens) == 2 && (tokens[0] == "parameters" || tokens[0] == "responses") {
+			return []string{tokens[0]}
+		}
+		return []string{"definitions"}
+	}
+
+	if len(tokens) == 3 && tokens[0] == "components" {
+		return []string{"components", tokens[1]}
+	}
+	return []string{"components", "schemas"}
+}
+
+// Append a numeric suffix if an imported component would collide with an existing one
+func uniqueComponentName(container map[string]any, name string) string {
+	if _, ok := container[name]; !ok {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", name, i)
+		if _, ok := container[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+// Resolve a reference relative to the document it appears in (a url or a file path)
+// scheme://host of a url, or empty if location isn't remote
+func remoteOrigin(location string) string {
+	if !IsRemoteLocation(location) {
+		return ""
+	}
+
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host)
+}
+
+func resolveLocation(base, ref string) string {
+	if IsRemoteLocation(ref) {
+		return ref
+	}
+
+	if IsRemoteLocation(base) {
+		baseUrl, err := url.Parse(base)
+		if err != nil {
+			return ref
+		}
+
+		refUrl, err := url.Parse(ref)
+		if err != nil {
+			return ref
+		}
+
+		return baseUrl.ResolveReference(refUrl).String()
+	}
+
+	location, fragment, hasFragment := strings.Cut(ref, "#")
+	if !filepath.IsAbs(location) {
+		baseDir := "."
+		if base != "-" {
+			baseDir = filepath.Dir(base)
+		}
+		location = filepath.Join(baseDir, location)
+	}
+
+	// Absolute paths keep refs stable once they are copied out of the document they appeared in
+	if absLocation, err := filepath.Abs(location); err == nil {
+		location = absLocation
+	}
+
+	if hasFragment {
+		return location + "#" + fragment
+	}
+	return location
+}
+
+func absolutizeLocalRefs(node any, location string) {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			ref, ok := value.(string)
+			if key == "$ref" && ok && strings.HasPrefix(ref, "#") {
+				v[key] = location + ref
+				continue
+			}
+			absolutizeLocalRefs(value, location)
+		}
+	case []any:
+		for _, value := range v {
+			absolutizeLocalRefs(value, location)
+		}
+	}
+}
+
+// https://datatracker.ietf.org/doc/html/rfc6901
+func resolvePointer(doc map[string]any, fragment string) (any, error) {
+	var node any = doc
+	for _, token := range pointerTokens(fragment) {
+		switch v := node.(type) {
+		case map[string]any:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", fragment)
+			}
+			node = child
+		case []any:
+			var index int
+			_, err := fmt.Sscanf(token, "%d", &index)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("%s not found", fragment)
+			}
+			node = v[index]
+		default:
+			return nil, fmt.Errorf("%s not found", fragment)
+		}
+	}
+
+	return node, nil
+}
+
+func pointerTokens(fragment string) []string {
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+
+	fragment = strings.TrimPrefix(fragment, "/")
+	if fragment == "" {
+		return nil
+	}
+
+	tokens := strings.Split(fragment, "/")
+	for i, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return tokens
+}
+
+// i.e. /components/schemas/Foo%20Bar -> /components/schemas/Foo Bar
+func canonicalPointer(fragment string) string {
+	pointer := ""
+	for _, token := range pointerTokens(fragment) {
+		pointer += "/" + escapePointerToken(token)
+	}
+	return pointer
+}
+
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+func ensureMap(parent map[string]any, key string) map[string]any {
+	m, ok := parent[key].(map[string]any)
+	if !ok {
+		m = map[string]any{}
+		parent[key] = m
+	}
+	return m
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[key] = deepCopy(item)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, item := range v {
+			s[i] = deepCopy(item)
+		}
+		return s
+	default:
+		return value
+	}
+}
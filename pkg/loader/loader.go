@@ -0,0 +1,224 @@
+// Package loader reads OpenAPI documents and normalizes them into the OpenAPI 3.1
+// model consumed by the typedfetch generator.
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/swaggest/openapi-go/openapi31"
+	"gopkg.in/yaml.v3"
+)
+
+type Options struct {
+	// Timeout for each remote request
+	Timeout time.Duration
+
+	// Extra headers (i.e. Authorization) sent with remote requests to the root document's origin; external $refs to other hosts don't get them
+	Headers map[string]string
+
+	// Directory where remote documents are cached by ETag; caching is disabled if empty
+	CacheDir string
+
+	// Fall back to the cached copy when the server rejects the request (4xx), not only when it's unreachable or fails
+	AllowStaleCache bool
+
+	// Called for every warning, i.e. when a remote document can't be cached or the cached copy is used instead
+	OnWarning func(message string)
+}
+
+// An OpenAPI document normalized to OpenAPI 3.1, along with where each of its nodes came from
+type Document struct {
+	Reflector *openapi31.Reflector
+
+	// Local files the document was read from (the root document and every externally referenced file), sorted.
+	// Urls and stdin are left out, since they can't be watched for changes.
+	Sources []string
+
+	// Positions of the root document's nodes; nil if the document was converted from swagger 2.0
+	positions map[string]Position
+
+	// Components imported from external documents, see bundler
+	origins           map[string]origin
+	externalPositions map[string]map[string]Position
+}
+
+// Position of the node at the given JSON Pointer in its source file.
+// Nodes synthesized while loading report the position of their closest ancestor.
+func (d *Document) Position(pointer string) (Position, bool) {
+	for candidate := pointer; ; candidate = parentPointer(candidate) {
+		if origin, ok := d.origins[candidate]; ok {
+			return nearestPosition(d.externalPositions[origin.location], origin.pointer+strings.TrimPrefix(pointer, candidate))
+		}
+
+		if candidate == "" {
+			break
+		}
+	}
+
+	return nearestPosition(d.positions, pointer)
+}
+
+// Load an OpenAPI document from a file path, an http(s) url, or from stdin if location is "-".
+// External $refs (other files or urls) are resolved relative to location and bundled into the document.
+func Load(location string, options Options) (*Document, error) {
+	specBytes, err := readLocation(location, options)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseDocument(specBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+
+	b, err := bundleExternalRefs(doc, location, options)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+
+	// Pointers into a converted swagger 2.0 document don't match the original file
+	var positions map[string]Position
+	if _, isSwagger := doc["swagger"]; !isSwagger {
+		positions = buildPositions(displayLocation(location), specBytes)
+	}
+
+	reflector, err := LoadDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+
+	return &Document{
+		Reflector:         reflector,
+		Sources:           b.sources(location),
+		positions:         positions,
+		origins:           b.origins,
+		externalPositions: b.positions,
+	}, nil
+}
+
+func displayLocation(location string) string {
+	if location == "-" {
+		return "<stdin>"
+	}
+	return location
+}
+
+func readLocation(location string, options Options) ([]byte, error) {
+	if location == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	if IsRemoteLocation(location) {
+		return fetchRemote(location, options)
+	}
+
+	return os.ReadFile(location)
+}
+
+// Load a JSON or YAML OpenAPI document; the format is detected from the content rather than a file extension
+func LoadBytes(specBytes []byte) (*openapi31.Reflector, error) {
+	doc, err := parseDocument(specBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadDocument(doc)
+}
+
+func parseDocument(specBytes []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	if isJson(specBytes) {
+		err := json.Unmarshal(specBytes, &doc)
+		if err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+
+	var value any
+	err := yaml.Unmarshal(specBytes, &value)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, ok := stringifyYamlKeys(value).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a mapping at the document root")
+	}
+
+	return doc, nil
+}
+
+// A JSON document must be an object at the root, whereas a YAML document can't start with {
+// unless it's a flow mapping, which the YAML parser handles just as well
+func isJson(specBytes []byte) bool {
+	trimmed := bytes.TrimPrefix(specBytes, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	trimmed = bytes.TrimLeft(trimmed, " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte("{")) && json.Valid(trimmed)
+}
+
+// Detect the document version, normalize it to OpenAPI 3.1, and load it into a reflector
+func LoadDocument(doc map[string]any) (*openapi31.Reflector, error) {
+	version, err := DetectVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == SpecVersionSwagger20 {
+		doc, err = convertSwagger20(doc)
+		if err != nil {
+			return nil, err
+		}
+		version = SpecVersionOpenApi30
+	}
+
+	if version == SpecVersionOpenApi30 {
+		normalizeOpenApi30(doc)
+	} else {
+		// An unquoted openapi: 3.1 is a number, which the reflector rejects
+		doc["openapi"] = versionString(doc["openapi"])
+	}
+
+	specBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	reflector := openapi31.NewReflector()
+	err = reflector.Spec.UnmarshalJSON(specBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return reflector, nil
+}
+
+// YAML allows non-string keys (i.e. unquoted response codes like 200:), which JSON can't represent
+func stringifyYamlKeys(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = stringifyYamlKeys(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprintf("%v", key)] = stringifyYamlKeys(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = stringifyYamlKeys(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,111 @@
+package loader
+
+// Rewrite an OpenAPI 3.0 document in place so that it is a valid OpenAPI 3.1 document
+// See: https://www.openapis.org/blog/2021/02/16/migrating-from-openapi-3-0-to-3-1-0
+func normalizeOpenApi30(doc map[string]any) {
+	walkDocumentSchemas(doc, normalizeSchema30)
+	doc["openapi"] = "3.1.0"
+}
+
+func normalizeSchema30(schema map[string]any) {
+	normalizeExclusiveBound(schema, "exclusiveMinimum", "minimum")
+	normalizeExclusiveBound(schema, "exclusiveMaximum", "maximum")
+
+	// 3.0 only allowed a single example on schemas; 3.1 uses the JSON Schema examples array
+	if example, ok := schema["example"]; ok {
+		if _, hasExamples := schema["examples"]; !hasExamples {
+			schema["examples"] = []any{example}
+		}
+		delete(schema, "example")
+	}
+
+	// x-nullable is a common vendor extension carried over from swagger 2.0
+	nullable := schema["nullable"] == true || schema["x-nullable"] == true
+	delete(schema, "nullable")
+	delete(schema, "x-nullable")
+	if nullable {
+		makeSchemaNullable(schema)
+	}
+}
+
+// 3.0: exclusiveMinimum: true, minimum: 5
+// 3.1: exclusiveMinimum: 5
+func normalizeExclusiveBound(schema map[string]any, exclusiveKey, boundKey string) {
+	exclusive, ok := schema[exclusiveKey].(bool)
+	if !ok {
+		return
+	}
+
+	delete(schema, exclusiveKey)
+	if bound, ok := schema[boundKey]; exclusive && ok {
+		schema[exclusiveKey] = bound
+		delete(schema, boundKey)
+	}
+}
+
+func makeSchemaNullable(schema map[string]any) {
+	// An enum only allows its listed values, with or without a type: enum: [a, b] -> enum: [a, b, null]
+	if enum, ok := schema["enum"].([]any); ok && !containsNil(enum) {
+		schema["enum"] = append(enum, nil)
+	}
+
+	// type: string -> type: [string, "null"]
+	if schemaType, ok := schema["type"].(string); ok {
+		schema["type"] = []any{schemaType, "null"}
+		return
+	}
+
+	// $ref and composed schemas can't carry a type, so wrap them: anyOf: [<schema>, {type: "null"}]
+	inner := map[string]any{}
+	for _, keyword := range []string{"$ref", "allOf", "anyOf", "oneOf"} {
+		if value, ok := schema[keyword]; ok {
+			inner[keyword] = value
+			delete(schema, keyword)
+		}
+	}
+
+	if len(inner) > 0 {
+		schema["anyOf"] = []any{inner, map[string]any{"type": "null"}}
+		return
+	}
+
+	// An enum without a type is typed after its values: enum: [a, b] -> type: [string, "null"], enum: [a, b, null]
+	if enumType, ok := enumJsonType(asSlice(schema["enum"])); ok {
+		schema["type"] = []any{enumType, "null"}
+	}
+}
+
+// The JSON type shared by every (non-null) value of an enum
+func enumJsonType(enum []any) (string, bool) {
+	enumType := ""
+	for _, value := range enum {
+		valueType := ""
+		switch value.(type) {
+		case nil:
+			continue
+		case string:
+			valueType = "string"
+		case bool:
+			valueType = "boolean"
+		case int, int64, uint64, float64:
+			valueType = "number"
+		default:
+			return "", false
+		}
+
+		if enumType != "" && enumType != valueType {
+			return "", false
+		}
+		enumType = valueType
+	}
+	return enumType, enumType != ""
+}
+
+func containsNil(values []any) bool {
+	for _, value := range values {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSchema30(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// The 3.0 schema and its 3.1 equivalent, in YAML
+		schema string
+		want   string
+	}{
+		{name: "nullable type", schema: "{type: string, nullable: true}", want: "{type: [string, 'null']}"},
+		{name: "not nullable", schema: "{type: string, nullable: false}", want: "{type: string}"},
+		{name: "x-nullable", schema: "{type: integer, x-nullable: true}", want: "{type: [integer, 'null']}"},
+		{
+			name:   "nullable enum",
+			schema: "{type: string, enum: [dog, cat], nullable: true}",
+			want:   "{type: [string, 'null'], enum: [dog, cat, null]}",
+		},
+		{
+			name:   "nullable enum without a type",
+			schema: "{enum: [dog, cat], nullable: true}",
+			want:   "{type: [string, 'null'], enum: [dog, cat, null]}",
+		},
+		{
+			name:   "nullable number enum without a type",
+			schema: "{enum: [1, 2.5], nullable: true}",
+			want:   "{type: [number, 'null'], enum: [1, 2.5, null]}",
+		},
+		{
+			name:   "nullable mixed enum without a type",
+			schema: "{enum: [1, one], nullable: true}",
+			want:   "{enum: [1, one, null]}",
+		},
+		{
+			name:   "nullable enum with null",
+			schema: "{type: string, enum: [dog, null], nullable: true}",
+			want:   "{type: [string, 'null'], enum: [dog, null]}",
+		},
+		{
+			name:   "nullable reference",
+			schema: "{$ref: '#/components/schemas/Pet', nullable: true}",
+			want:   "{anyOf: [{$ref: '#/components/schemas/Pet'}, {type: 'null'}]}",
+		},
+		{
+			name:   "nullable allOf",
+			schema: "{allOf: [{$ref: '#/components/schemas/Pet'}], description: A pet, nullable: true}",
+			want:   "{anyOf: [{allOf: [{$ref: '#/components/schemas/Pet'}]}, {type: 'null'}], description: A pet}",
+		},
+		{name: "nullable without a type", schema: "{description: Anything, nullable: true}", want: "{description: Anything}"},
+		{name: "exclusiveMinimum", schema: "{type: integer, minimum: 5, exclusiveMinimum: true}", want: "{type: integer, exclusiveMinimum: 5}"},
+		{name: "exclusiveMaximum", schema: "{type: integer, maximum: 10, exclusiveMaximum: true}", want: "{type: integer, exclusiveMaximum: 10}"},
+		{name: "inclusive bound", schema: "{type: integer, minimum: 5, exclusiveMinimum: false}", want: "{type: integer, minimum: 5}"},
+		{name: "exclusive without a bound", schema: "{type: integer, exclusiveMaximum: true}", want: "{type: integer}"},
+		{name: "3.1 exclusive bound", schema: "{type: integer, exclusiveMinimum: 5}", want: "{type: integer, exclusiveMinimum: 5}"},
+		{name: "example", schema: "{type: string, example: dog}", want: "{type: string, examples: [dog]}"},
+		{name: "example and examples", schema: "{type: string, example: dog, examples: [cat]}", want: "{type: string, examples: [cat]}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema := yamlValue(t, test.schema).(map[string]any)
+			normalizeSchema30(schema)
+
+			want := yamlValue(t, test.want)
+			if !reflect.DeepEqual(schema, want) {
+				t.Errorf("got %s, want %s", valueJson(schema), valueJson(want))
+			}
+		})
+	}
+}
+
+// Schemas are normalized wherever they appear, not only in components
+func TestNormalizeOpenApi30(t *testing.T) {
+	doc := yamlValue(t, "openapi: 3.0.3\n"+
+		"paths: {/pets: {get: {parameters: [{name: limit, in: query, schema: {type: integer, nullable: true}}], "+
+		"responses: {'200': {description: ok, content: {application/json: {schema: {type: array, items: {type: string, example: dog}}}}}}}}}\n"+
+		"components: {schemas: {Pet: {type: object, properties: {age: {type: integer, minimum: 0, exclusiveMinimum: true}}}}}").(map[string]any)
+	normalizeOpenApi30(doc)
+
+	want := yamlValue(t, "openapi: 3.1.0\n"+
+		"paths: {/pets: {get: {parameters: [{name: limit, in: query, schema: {type: [integer, 'null']}}], "+
+		"responses: {'200': {description: ok, content: {application/json: {schema: {type: array, items: {type: string, examples: [dog]}}}}}}}}}\n"+
+		"components: {schemas: {Pet: {type: object, properties: {age: {type: integer, exclusiveMinimum: 0}}}}}")
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("got %s, want %s", valueJson(doc), valueJson(want))
+	}
+}
@@ -0,0 +1,76 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Map every JSON Pointer in a JSON or YAML file to the position of its node.
+// Mapping entries point at their key, since that's where an editor should jump to.
+func buildPositions(file string, specBytes []byte) map[string]Position {
+	positions := map[string]Position{}
+
+	var root yaml.Node
+	err := yaml.Unmarshal(specBytes, &root)
+	if err != nil || len(root.Content) == 0 {
+		return positions
+	}
+
+	node := root.Content[0]
+	positions[""] = Position{File: file, Line: node.Line, Column: node.Column}
+	collectPositions(file, node, "", positions)
+	return positions
+}
+
+func collectPositions(file string, node *yaml.Node, pointer string, positions map[string]Position) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPointer := pointer + "/" + escapePointerToken(key.Value)
+			positions[childPointer] = Position{File: file, Line: key.Line, Column: key.Column}
+			collectPositions(file, value, childPointer, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childPointer := fmt.Sprintf("%s/%d", pointer, i)
+			positions[childPointer] = Position{File: file, Line: item.Line, Column: item.Column}
+			collectPositions(file, item, childPointer, positions)
+		}
+	}
+}
+
+// Find the position of pointer, or of its closest ancestor if the node was synthesized during normalization
+func nearestPosition(positions map[string]Position, pointer string) (Position, bool) {
+	for {
+		if position, ok := positions[pointer]; ok {
+			return position, true
+		}
+
+		if pointer == "" {
+			return Position{}, false
+		}
+
+		pointer = parentPointer(pointer)
+	}
+}
+
+func parentPointer(pointer string) string {
+	i := strings.LastIndex(pointer, "/")
+	if i < 0 {
+		return ""
+	}
+	return pointer[:i]
+}
@@ -0,0 +1,133 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Whether location is an http(s) URL rather than a file path
+func IsRemoteLocation(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// Fetch a remote document, revalidating against the on-disk cache with If-None-Match.
+// If the server can't be reached or fails (5xx), the cached copy is used with a warning so generation still works offline.
+// A 4xx means the request itself is wrong (i.e. an expired token), so it fails unless options.AllowStaleCache is set.
+func fetchRemote(url string, options Options) ([]byte, error) {
+	cachedBody, cachedEtag := readCache(options.CacheDir, url)
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range options.Headers {
+		request.Header.Set(name, value)
+	}
+
+	if cachedBody != nil && cachedEtag != "" {
+		request.Header.Set("If-None-Match", cachedEtag)
+	}
+
+	client := &http.Client{Timeout: options.Timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		if cachedBody != nil {
+			warnStaleCache(url, err.Error(), options)
+			return cachedBody, nil
+		}
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified && cachedBody != nil {
+		return cachedBody, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		clientError := response.StatusCode >= 400 && response.StatusCode < 500
+		if cachedBody != nil && (!clientError || options.AllowStaleCache) {
+			warnStaleCache(url, "unexpected status "+response.Status, options)
+			return cachedBody, nil
+		}
+		return nil, fmt.Errorf("GET %s: unexpected status %s", url, response.Status)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The download succeeded, so a cache that can't be written only costs the next run a full download
+	err = writeCache(options.CacheDir, url, body, response.Header.Get("ETag"))
+	if err != nil && options.OnWarning != nil {
+		options.OnWarning(fmt.Sprintf("can't cache %s: %s", url, err))
+	}
+
+	return body, nil
+}
+
+func warnStaleCache(url, reason string, options Options) {
+	if options.OnWarning != nil {
+		options.OnWarning(fmt.Sprintf("using the cached copy of %s: %s", url, reason))
+	}
+}
+
+// Cache entries are named after the sha256 of the url: <hash>.body holds the document, <hash>.etag its ETag
+func cachePaths(cacheDir, url string) (string, string) {
+	hash := sha256.Sum256([]byte(url))
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(cacheDir, name+".body"), filepath.Join(cacheDir, name+".etag")
+}
+
+func readCache(cacheDir, url string) ([]byte, string) {
+	if cacheDir == "" {
+		return nil, ""
+	}
+
+	bodyPath, etagPath := cachePaths(cacheDir, url)
+	body, err := os.ReadFile(bodyPath)
+	if err != nil {
+		return nil, ""
+	}
+
+	etag, err := os.ReadFile(etagPath)
+	if err != nil {
+		return body, ""
+	}
+
+	return body, string(etag)
+}
+
+func writeCache(cacheDir, url string, body []byte, etag string) error {
+	if cacheDir == "" {
+		return nil
+	}
+
+	err := os.MkdirAll(cacheDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	bodyPath, etagPath := cachePaths(cacheDir, url)
+	err = os.WriteFile(bodyPath, body, 0600)
+	if err != nil {
+		return err
+	}
+
+	if etag == "" {
+		err = os.Remove(etagPath)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return os.WriteFile(etagPath, []byte(etag), 0600)
+}
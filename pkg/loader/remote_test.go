@@ -0,0 +1,213 @@
+package loader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const remoteDocument = "openapi: 3.1.0\n"
+
+// Serves remoteDocument with an ETag, or the given status
+func newRemoteServer(t *testing.T, status int) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	ifNoneMatch := &[]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ifNoneMatch = append(*ifNoneMatch, r.Header.Get("If-None-Match"))
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(remoteDocument))
+	}))
+	t.Cleanup(server.Close)
+	return server, ifNoneMatch
+}
+
+func TestFetchRemote(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// Response of the server, or 0 if it's unreachable
+		status          int
+		cached          bool
+		allowStaleCache bool
+
+		want            string
+		wantErr         bool
+		wantIfNoneMatch string
+
+		// Whether the cached copy is used in place of the response, which is reported as a warning
+		wantWarning bool
+	}{
+		{name: "200", status: http.StatusOK, want: remoteDocument},
+		{name: "304", status: http.StatusOK, cached: true, want: "cached", wantIfNoneMatch: `"v1"`},
+		{name: "offline", cached: true, want: "cached", wantWarning: true},
+		{name: "offline without cache", wantErr: true},
+		{name: "500", status: http.StatusInternalServerError, cached: true, want: "cached", wantIfNoneMatch: `"v1"`, wantWarning: true},
+		{name: "500 without cache", status: http.StatusInternalServerError, wantErr: true},
+		{name: "401", status: http.StatusUnauthorized, cached: true, wantErr: true},
+		{name: "404", status: http.StatusNotFound, cached: true, wantErr: true},
+		{
+			name:            "401 with a stale cache allowed",
+			status:          http.StatusUnauthorized,
+			cached:          true,
+			allowStaleCache: true,
+			want:            "cached",
+			wantIfNoneMatch: `"v1"`,
+			wantWarning:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			warnings := []string{}
+			options := Options{
+				CacheDir:        t.TempDir(),
+				AllowStaleCache: test.allowStaleCache,
+				OnWarning:       func(message string) { warnings = append(warnings, message) },
+			}
+
+			url := "http://127.0.0.1:0/openapi.yaml"
+			ifNoneMatch := &[]string{}
+			if test.status != 0 {
+				var server *httptest.Server
+				server, ifNoneMatch = newRemoteServer(t, test.status)
+				url = server.URL + "/openapi.yaml"
+			}
+
+			if test.cached {
+				err := writeCache(options.CacheDir, url, []byte("cached"), `"v1"`)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			body, err := fetchRemote(url, options)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(body) != test.want {
+				t.Errorf("body = %q, want %q", body, test.want)
+			}
+			if test.status != 0 && (*ifNoneMatch)[0] != test.wantIfNoneMatch {
+				t.Errorf("If-None-Match = %q, want %q", (*ifNoneMatch)[0], test.wantIfNoneMatch)
+			}
+			if (len(warnings) != 0) != test.wantWarning {
+				t.Errorf("warnings = %q, want a warning: %v", warnings, test.wantWarning)
+			}
+		})
+	}
+}
+
+func TestFetchRemoteCache(t *testing.T) {
+	server, ifNoneMatch := newRemoteServer(t, http.StatusOK)
+	url := server.URL + "/openapi.yaml"
+	options := Options{CacheDir: t.TempDir()}
+
+	for i := 0; i < 2; i++ {
+		body, err := fetchRemote(url, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != remoteDocument {
+			t.Errorf("body = %q, want %q", body, remoteDocument)
+		}
+	}
+
+	// The second request revalidates the cached copy
+	if len(*ifNoneMatch) != 2 || (*ifNoneMatch)[0] != "" || (*ifNoneMatch)[1] != `"v1"` {
+		t.Errorf("If-None-Match = %q, want [\"\" \"v1\"]", *ifNoneMatch)
+	}
+
+	bodyPath, _ := cachePaths(options.CacheDir, url)
+	info, err := os.Stat(bodyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("cache file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestFetchRemoteCacheNotWritable(t *testing.T) {
+	server, _ := newRemoteServer(t, http.StatusOK)
+
+	// A file where the cache directory should be
+	cacheDir := t.TempDir() + "/cache"
+	err := os.WriteFile(cacheDir, nil, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := []string{}
+	options := Options{CacheDir: cacheDir, OnWarning: func(message string) { warnings = append(warnings, message) }}
+
+	body, err := fetchRemote(server.URL+"/openapi.yaml", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != remoteDocument {
+		t.Errorf("body = %q, want %q", body, remoteDocument)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %q, want one", warnings)
+	}
+}
+
+// Headers are only sent to the root document's origin, not to the hosts of its external $refs
+func TestLoadHeaders(t *testing.T) {
+	const common = "components: {schemas: {Error: {type: string}}}\n"
+
+	authorizations := map[string]string{}
+	handler := func(document string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			authorizations[r.Host+r.URL.Path] = r.Header.Get("Authorization")
+			w.Write([]byte(document))
+		}
+	}
+
+	other := httptest.NewServer(handler(common))
+	t.Cleanup(other.Close)
+
+	mux := http.NewServeMux()
+	root := httptest.NewServer(mux)
+	t.Cleanup(root.Close)
+	mux.Handle("/common.yaml", handler(common))
+	mux.Handle("/openapi.yaml", handler("openapi: 3.1.0\ninfo: {title: test, version: '1'}\npaths: {}\ncomponents:\n  schemas:\n"+
+		"    Local: {$ref: './common.yaml#/components/schemas/Error'}\n"+
+		"    Other: {$ref: '"+other.URL+"/common.yaml#/components/schemas/Error'}\n"))
+
+	_, err := Load(root.URL+"/openapi.yaml", Options{Headers: map[string]string{"Authorization": "Bearer secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootHost := strings.TrimPrefix(root.URL, "http://")
+	otherHost := strings.TrimPrefix(other.URL, "http://")
+	want := map[string]string{
+		rootHost + "/openapi.yaml": "Bearer secret",
+		rootHost + "/common.yaml":  "Bearer secret",
+		otherHost + "/common.yaml": "",
+	}
+	if !reflect.DeepEqual(authorizations, want) {
+		t.Errorf("Authorization by request = %q, want %q", authorizations, want)
+	}
+}
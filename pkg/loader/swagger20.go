@@ -0,0 +1,499 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keywords shared by swagger 2.0 non-body parameters, headers, and items objects that map 1:1 onto a schema
+var swaggerSchemaKeywords = []string{
+	"type", "format", "enum", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf", "x-nullable",
+}
+
+// Convert a Swagger 2.0 document into an equivalent OpenAPI 3.0 document
+// See: https://swagger.io/docs/specification/2-0/basic-structure/ and https://spec.openapis.org/oas/v3.0.3
+func convertSwagger20(swagger map[string]any) (map[string]any, error) {
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info":    swagger["info"],
+	}
+	copyKeys(doc, swagger, "tags", "security", "externalDocs")
+	copyExtensions(doc, swagger)
+
+	if servers := convertSwaggerServers(swagger); len(servers) > 0 {
+		doc["servers"] = servers
+	}
+
+	components, err := convertSwaggerComponents(swagger)
+	if err != nil {
+		return nil, err
+	}
+	doc["components"] = components
+
+	paths := map[string]any{}
+	for path, pathItem := range asMap(swagger["paths"]) {
+		convertedPathItem, err := convertSwaggerPathItem(swagger, asMap(pathItem))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		paths[path] = convertedPathItem
+	}
+	doc["paths"] = paths
+
+	rewriteSwaggerRefs(doc)
+	walkDocumentSchemas(doc, convertSwaggerSchema)
+
+	return doc, nil
+}
+
+// host + basePath + schemes -> servers
+func convertSwaggerServers(swagger map[string]any) []any {
+	host, _ := swagger["host"].(string)
+	basePath, _ := swagger["basePath"].(string)
+	if host == "" && basePath == "" {
+		return nil
+	}
+
+	if host == "" {
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	servers := []any{}
+	for _, scheme := range stringList(swagger["schemes"], []string{"https"}) {
+		servers = append(servers, map[string]any{"url": fmt.Sprintf("%s://%s%s", scheme, host, basePath)})
+	}
+	return servers
+}
+
+func convertSwaggerComponents(swagger map[string]any) (map[string]any, error) {
+	consumes := stringList(swagger["consumes"], []string{"application/json"})
+	produces := stringList(swagger["produces"], []string{"application/json"})
+
+	components := map[string]any{}
+	if definitions := asMap(swagger["definitions"]); definitions != nil {
+		components["schemas"] = definitions
+	}
+
+	// Body parameters become request bodies; formData parameters are inlined wherever they are referenced
+	parameters := map[string]any{}
+	requestBodies := map[string]any{}
+	for name, parameter := range asMap(swagger["parameters"]) {
+		param := asMap(parameter)
+		switch param["in"] {
+		case "body":
+			requestBodies[name] = convertSwaggerBodyParameter(param, consumes)
+		case "formData":
+			continue
+		default:
+			parameters[name] = convertSwaggerParameter(param)
+		}
+	}
+
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+
+	responses := map[string]any{}
+	for name, response := range asMap(swagger["responses"]) {
+		responses[name] = convertSwaggerResponse(asMap(response), produces)
+	}
+
+	if len(responses) > 0 {
+		components["responses"] = responses
+	}
+
+	securitySchemes := map[string]any{}
+	for name, securityDefinition := range asMap(swagger["securityDefinitions"]) {
+		securityScheme, err := convertSwaggerSecurityDefinition(asMap(securityDefinition))
+		if err != nil {
+			return nil, fmt.Errorf("securityDefinitions: %s: %v", name, err)
+		}
+		securitySchemes[name] = securityScheme
+	}
+
+	if len(securitySchemes) > 0 {
+		components["securitySchemes"] = securitySchemes
+	}
+
+	return components, nil
+}
+
+func convertSwaggerPathItem(swagger map[string]any, pathItem map[string]any) (map[string]any, error) {
+	converted := map[string]any{}
+	copyKeys(converted, pathItem, "summary", "description")
+	copyExtensions(converted, pathItem)
+
+	for _, method := range httpMethods {
+		operation := asMap(pathItem[method])
+		if operation == nil {
+			continue
+		}
+
+		convertedOperation, err := convertSwaggerOperation(swagger, operation, asSlice(pathItem["parameters"]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", method, err)
+		}
+		converted[method] = convertedOperation
+	}
+
+	return converted, nil
+}
+
+// Path-level parameters are merged into each operation (operation parameters take precedence),
+// since body and formData parameters have to end up in each operation's requestBody anyway
+func convertSwaggerOperation(swagger map[string]any, operation map[string]any, pathParameters []any) (map[string]any, error) {
+	converted := map[string]any{}
+	for key, value := range operation {
+		switch key {
+		case "parameters", "responses", "consumes", "produces", "schemes":
+			continue
+		default:
+			converted[key] = value
+		}
+	}
+
+	consumes := stringList(operation["consumes"], stringList(swagger["consumes"], []string{"application/json"}))
+	produces := stringList(operation["produces"], stringList(swagger["produces"], []string{"application/json"}))
+
+	parameters, err := mergeSwaggerParameters(swagger, pathParameters, asSlice(operation["parameters"]))
+	if err != nil {
+		return nil, err
+	}
+
+	convertedParameters := []any{}
+	formDataParameters := []map[string]any{}
+	for _, parameter := range parameters {
+		switch parameter.resolved["in"] {
+		case "body":
+			if parameter.ref != "" {
+				requestBodyName := strings.TrimPrefix(parameter.ref, "#/parameters/")
+				converted["requestBody"] = map[string]any{"$ref": "#/components/requestBodies/" + requestBodyName}
+			} else {
+				converted["requestBody"] = convertSwaggerBodyParameter(parameter.resolved, consumes)
+			}
+		case "formData":
+			formDataParameters = append(formDataParameters, parameter.resolved)
+		default:
+			if parameter.ref != "" {
+				convertedParameters = append(convertedParameters, map[string]any{"$ref": parameter.ref})
+			} else {
+				convertedParameters = append(convertedParameters, convertSwaggerParameter(parameter.resolved))
+			}
+		}
+	}
+
+	if len(convertedParameters) > 0 {
+		converted["parameters"] = convertedParameters
+	}
+
+	if len(formDataParameters) > 0 {
+		converted["requestBody"] = convertSwaggerFormDataParameters(formDataParameters, consumes)
+	}
+
+	responses := map[string]any{}
+	for code, response := range asMap(operation["responses"]) {
+		resp := asMap(response)
+		if _, ok := resp["$ref"]; ok {
+			responses[code] = resp
+			continue
+		}
+		responses[code] = convertSwaggerResponse(resp, produces)
+	}
+	converted["responses"] = responses
+
+	return converted, nil
+}
+
+type swaggerParameter struct {
+	ref      string
+	resolved map[string]any
+}
+
+func mergeSwaggerParameters(swagger map[string]any, pathParameters, operationParameters []any) ([]swaggerParameter, error) {
+	merged := []swaggerParameter{}
+	indexByKey := map[string]int{}
+
+	for _, parameter := range append(append([]any{}, pathParameters...), operationParameters...) {
+		param := asMap(parameter)
+		resolved := param
+		ref, _ := param["$ref"].(string)
+		if ref != "" {
+			name := strings.TrimPrefix(ref, "#/parameters/")
+			resolved = asMap(asMap(swagger["parameters"])[name])
+			if resolved == nil {
+				return nil, fmt.Errorf("parameter %s not found", ref)
+			}
+		}
+
+		// A parameter is uniquely identified by its name and location
+		key := fmt.Sprintf("%v:%v", resolved["in"], resolved["name"])
+		if resolved["in"] == "body" {
+			key = "body"
+		}
+
+		p := swaggerParameter{ref: ref, resolved: resolved}
+		if i, ok := indexByKey[key]; ok {
+			merged[i] = p
+			continue
+		}
+
+		indexByKey[key] = len(merged)
+		merged = append(merged, p)
+	}
+
+	return merged, nil
+}
+
+func convertSwaggerParameter(param map[string]any) map[string]any {
+	converted := map[string]any{}
+	copyKeys(converted, param, "name", "in", "description", "required", "allowEmptyValue", "deprecated")
+	copyExtensions(converted, param)
+
+	// https://swagger.io/docs/specification/serialization/
+	switch param["collectionFormat"] {
+	case "csv":
+		if param["in"] == "query" || param["in"] == "cookie" {
+			converted["style"] = "form"
+			converted["explode"] = false
+		} else {
+			converted["style"] = "simple"
+		}
+	case "multi":
+		converted["style"] = "form"
+		converted["explode"] = true
+	case "ssv":
+		converted["style"] = "spaceDelimited"
+	case "pipes":
+		converted["style"] = "pipeDelimited"
+	}
+
+	converted["schema"] = swaggerItemsToSchema(param)
+	return converted
+}
+
+// Non-body parameters, headers, and items objects describe their type inline rather than with a schema
+func swaggerItemsToSchema(items map[string]any) map[string]any {
+	schema := map[string]any{}
+	copyKeys(schema, items, swaggerSchemaKeywords...)
+
+	if nestedItems := asMap(items["items"]); nestedItems != nil {
+		schema["items"] = swaggerItemsToSchema(nestedItems)
+	}
+
+	return schema
+}
+
+func convertSwaggerBodyParameter(param map[string]any, consumes []string) map[string]any {
+	converted := map[string]any{
+		"content": swaggerContent(param["schema"], consumes, nil),
+	}
+	copyKeys(converted, param, "description", "required")
+	copyExtensions(converted, param)
+	return converted
+}
+
+// All formData parameters of an operation are combined into a single object schema
+func convertSwaggerFormDataParameters(params []map[string]any, consumes []string) map[string]any {
+	properties := map[string]any{}
+	required := []any{}
+	hasFile := false
+	for _, param := range params {
+		name := fmt.Sprintf("%v", param["name"])
+		property := swaggerItemsToSchema(param)
+		copyKeys(property, param, "description")
+		properties[name] = property
+
+		if param["required"] == true {
+			required = append(required, name)
+		}
+
+		if param["type"] == "file" {
+			hasFile = true
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	formContentTypes := []string{}
+	for _, contentType := range consumes {
+		if contentType == "multipart/form-data" || contentType == "application/x-www-form-urlencoded" {
+			formContentTypes = append(formContentTypes, contentType)
+		}
+	}
+
+	if len(formContentTypes) == 0 {
+		formContentTypes = []string{"application/x-www-form-urlencoded"}
+		if hasFile {
+			formContentTypes = []string{"multipart/form-data"}
+		}
+	}
+
+	return map[string]any{
+		"content":  swaggerContent(schema, formContentTypes, nil),
+		"required": len(required) > 0,
+	}
+}
+
+func convertSwaggerResponse(response map[string]any, produces []string) map[string]any {
+	// description is required in OpenAPI 3
+	converted := map[string]any{"description": ""}
+	copyKeys(converted, response, "description")
+	copyExtensions(converted, response)
+
+	if schema, ok := response["schema"]; ok {
+		converted["content"] = swaggerContent(schema, produces, asMap(response["examples"]))
+	}
+
+	headers := map[string]any{}
+	for name, header := range asMap(response["headers"]) {
+		h := asMap(header)
+		convertedHeader := map[string]any{"schema": swaggerItemsToSchema(h)}
+		copyKeys(convertedHeader, h, "description")
+		headers[name] = convertedHeader
+	}
+
+	if len(headers) > 0 {
+		converted["headers"] = headers
+	}
+
+	return converted
+}
+
+// Swagger 2.0 shares one schema across all mime types; OpenAPI 3 repeats it per media type
+func swaggerContent(schema any, contentTypes []string, examples map[string]any) map[string]any {
+	content := map[string]any{}
+	for _, contentType := range contentTypes {
+		mediaType := map[string]any{}
+		if schema != nil {
+			mediaType["schema"] = schema
+		}
+		if example, ok := examples[contentType]; ok {
+			mediaType["example"] = example
+		}
+		content[contentType] = mediaType
+	}
+	return content
+}
+
+func convertSwaggerSecurityDefinition(securityDefinition map[string]any) (map[string]any, error) {
+	converted := map[string]any{}
+	copyKeys(converted, securityDefinition, "description")
+	copyExtensions(converted, securityDefinition)
+
+	switch securityDefinition["type"] {
+	case "basic":
+		converted["type"] = "http"
+		converted["scheme"] = "basic"
+	case "apiKey":
+		converted["type"] = "apiKey"
+		copyKeys(converted, securityDefinition, "name", "in")
+	case "oauth2":
+		flow := map[string]any{"scopes": map[string]any{}}
+		copyKeys(flow, securityDefinition, "authorizationUrl", "tokenUrl", "scopes")
+
+		flowNames := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}
+		flowName, ok := flowNames[fmt.Sprintf("%v", securityDefinition["flow"])]
+		if !ok {
+			return nil, fmt.Errorf("unsupported oauth2 flow: %v", securityDefinition["flow"])
+		}
+
+		converted["type"] = "oauth2"
+		converted["flows"] = map[string]any{flowName: flow}
+	default:
+		return nil, fmt.Errorf("unsupported type: %v", securityDefinition["type"])
+	}
+
+	return converted, nil
+}
+
+// Point every $ref at its new location under components
+func rewriteSwaggerRefs(node any) {
+	refPrefixes := map[string]string{
+		"#/definitions/": "#/components/schemas/",
+		"#/parameters/":  "#/components/parameters/",
+		"#/responses/":   "#/components/responses/",
+	}
+
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			ref, ok := value.(string)
+			if key != "$ref" || !ok {
+				rewriteSwaggerRefs(value)
+				continue
+			}
+
+			for oldPrefix, newPrefix := range refPrefixes {
+				if strings.HasPrefix(ref, oldPrefix) {
+					v[key] = newPrefix + strings.TrimPrefix(ref, oldPrefix)
+					break
+				}
+			}
+		}
+	case []any:
+		for _, value := range v {
+			rewriteSwaggerRefs(value)
+		}
+	}
+}
+
+func convertSwaggerSchema(schema map[string]any) {
+	// type: file is only valid in swagger 2.0
+	if schema["type"] == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+
+	// discriminator: petType -> discriminator: {propertyName: petType}
+	if discriminator, ok := schema["discriminator"].(string); ok {
+		schema["discriminator"] = map[string]any{"propertyName": discriminator}
+	}
+}
+
+func stringList(value any, fallback []string) []string {
+	values := asSlice(value)
+	if len(values) == 0 {
+		return fallback
+	}
+
+	strs := []string{}
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+func copyKeys(dst, src map[string]any, keys ...string) {
+	for _, key := range keys {
+		if value, ok := src[key]; ok {
+			dst[key] = value
+		}
+	}
+}
+
+// Copy x- vendor extensions
+func copyExtensions(dst, src map[string]any) {
+	for key, value := range src {
+		if strings.HasPrefix(key, "x-") {
+			dst[key] = value
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package loader
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse a YAML value, with the same key types as a parsed document
+func yamlValue(t *testing.T, source string) any {
+	t.Helper()
+
+	var value any
+	err := yaml.Unmarshal([]byte(source), &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stringifyYamlKeys(value)
+}
+
+// JSON of a value, so that a mismatch is readable
+func valueJson(value any) string {
+	bytes, _ := json.Marshal(value)
+	return string(bytes)
+}
+
+func TestConvertSwagger20(t *testing.T) {
+	const header = "swagger: '2.0'\ninfo: {title: test, version: '1'}\n"
+	const ok = "responses: {'200': {description: ok}}"
+
+	tests := []struct {
+		name    string
+		swagger string
+
+		// The converted (and normalized to 3.1) node at pointer, in YAML
+		pointer string
+		want    string
+	}{
+		{
+			name:    "body parameter",
+			swagger: "paths: {/pets: {post: {parameters: [{in: body, name: pet, required: true, schema: {$ref: '#/definitions/Pet'}}], " + ok + "}}}",
+			pointer: "/paths/~1pets/post/requestBody",
+			want:    "{required: true, content: {application/json: {schema: {$ref: '#/components/schemas/Pet'}}}}",
+		},
+		{
+			name: "body parameter with consumes",
+			swagger: "consumes: [application/xml]\n" +
+				"paths: {/pets: {post: {consumes: [application/json, text/plain], parameters: [{in: body, name: pet, schema: {type: string}}], " + ok + "}}}",
+			pointer: "/paths/~1pets/post/requestBody/content",
+			want:    "{application/json: {schema: {type: string}}, text/plain: {schema: {type: string}}}",
+		},
+		{
+			name: "referenced body parameter",
+			swagger: "parameters: {Pet: {in: body, name: pet, schema: {type: object}}}\n" +
+				"paths: {/pets: {post: {parameters: [{$ref: '#/parameters/Pet'}], " + ok + "}}}",
+			pointer: "/paths/~1pets/post/requestBody",
+			want:    "{$ref: '#/components/requestBodies/Pet'}",
+		},
+		{
+			name:    "body component",
+			swagger: "parameters: {Pet: {in: body, name: pet, description: A pet, schema: {type: object}}}\npaths: {}",
+			pointer: "/components/requestBodies/Pet",
+			want:    "{description: A pet, content: {application/json: {schema: {type: object}}}}",
+		},
+		{
+			name: "form data",
+			swagger: "paths: {/pets: {post: {parameters: [" +
+				"{in: formData, name: name, required: true, type: string, description: The name}, {in: formData, name: age, type: integer}], " + ok + "}}}",
+			pointer: "/paths/~1pets/post/requestBody",
+			want: "{required: true, content: {application/x-www-form-urlencoded: {schema: {type: object, required: [name], properties: {" +
+				"name: {type: string, description: The name}, age: {type: integer}}}}}}",
+		},
+		{
+			name:    "form data with a file",
+			swagger: "paths: {/pets: {post: {parameters: [{in: formData, name: photo, type: file}], " + ok + "}}}",
+			pointer: "/paths/~1pets/post/requestBody",
+			want:    "{required: false, content: {multipart/form-data: {schema: {type: object, properties: {photo: {type: string, format: binary}}}}}}",
+		},
+		{
+			name: "form data with consumes",
+			swagger: "paths: {/pets: {post: {consumes: [application/json, multipart/form-data], " +
+				"parameters: [{in: formData, name: name, type: string}], " + ok + "}}}",
+			pointer: "/paths/~1pets/post/requestBody/content",
+			want:    "{multipart/form-data: {schema: {type: object, properties: {name: {type: string}}}}}",
+		},
+		{
+			name:    "csv query",
+			swagger: "paths: {/pets: {get: {parameters: [{in: query, name: tags, type: array, items: {type: string}, collectionFormat: csv}], " + ok + "}}}",
+			pointer: "/paths/~1pets/get/parameters/0",
+			want:    "{in: query, name: tags, style: form, explode: false, schema: {type: array, items: {type: string}}}",
+		},
+		{
+			name:    "csv path",
+			swagger: "paths: {'/pets/{ids}': {get: {parameters: [{in: path, name: ids, required: true, type: array, items: {type: integer}, collectionFormat: csv}], " + ok + "}}}",
+			pointer: "/paths/~1pets~1{ids}/get/parameters/0",
+			want:    "{in: path, name: ids, required: true, style: simple, schema: {type: array, items: {type: integer}}}",
+		},
+		{
+			name:    "multi",
+			swagger: "paths: {/pets: {get: {parameters: [{in: query, name: tags, type: array, items: {type: string}, collectionFormat: multi}], " + ok + "}}}",
+			pointer: "/paths/~1pets/get/parameters/0",
+			want:    "{in: query, name: tags, style: form, explode: true, schema: {type: array, items: {type: string}}}",
+		},
+		{
+			name:    "ssv",
+			swagger: "paths: {/pets: {get: {parameters: [{in: query, name: tags, type: array, items: {type: string}, collectionFormat: ssv}], " + ok + "}}}",
+			pointer: "/paths/~1pets/get/parameters/0/style",
+			want:    "spaceDelimited",
+		},
+		{
+			name:    "pipes",
+			swagger: "paths: {/pets: {get: {parameters: [{in: query, name: tags, type: array, items: {type: string}, collectionFormat: pipes}], " + ok + "}}}",
+			pointer: "/paths/~1pets/get/parameters/0/style",
+			want:    "pipeDelimited",
+		},
+		{
+			name: "path parameters merged into the operation",
+			swagger: "paths: {'/pets/{id}': {parameters: [{in: path, name: id, required: true, type: string}], " +
+				"get: {parameters: [{in: path, name: id, required: true, type: integer}], " + ok + "}}}",
+			pointer: "/paths/~1pets~1{id}/get/parameters",
+			want:    "[{in: path, name: id, required: true, schema: {type: integer}}]",
+		},
+		{
+			name:    "definition refs",
+			swagger: "definitions: {Pet: {type: object, properties: {owner: {$ref: '#/definitions/Owner'}}}, Owner: {type: string}}\npaths: {}",
+			pointer: "/components/schemas/Pet/properties/owner",
+			want:    "{$ref: '#/components/schemas/Owner'}",
+		},
+		{
+			name: "parameter refs",
+			swagger: "parameters: {Limit: {in: query, name: limit, type: integer}}\n" +
+				"paths: {/pets: {get: {parameters: [{$ref: '#/parameters/Limit'}], " + ok + "}}}",
+			pointer: "/paths/~1pets/get/parameters/0",
+			want:    "{$ref: '#/components/parameters/Limit'}",
+		},
+		{
+			name: "response refs",
+			swagger: "responses: {NotFound: {description: not found}}\n" +
+				"paths: {/pets: {get: {responses: {'404': {$ref: '#/responses/NotFound'}}}}}",
+			pointer: "/paths/~1pets/get/responses/404",
+			want:    "{$ref: '#/components/responses/NotFound'}",
+		},
+		{
+			name: "produces",
+			swagger: "produces: [application/json, application/xml]\n" +
+				"paths: {/pets: {get: {responses: {'200': {description: ok, schema: {type: string}, examples: {application/json: dog}}}}}}",
+			pointer: "/paths/~1pets/get/responses/200/content",
+			want:    "{application/json: {schema: {type: string}, example: dog}, application/xml: {schema: {type: string}}}",
+		},
+		{
+			name:    "operation produces",
+			swagger: "produces: [application/xml]\npaths: {/pets: {get: {produces: [text/plain], responses: {'200': {description: ok, schema: {type: string}}}}}}",
+			pointer: "/paths/~1pets/get/responses/200/content",
+			want:    "{text/plain: {schema: {type: string}}}",
+		},
+		{
+			name:    "response without a schema",
+			swagger: "paths: {/pets: {delete: {responses: {'204': {description: deleted}}}}}",
+			pointer: "/paths/~1pets/delete/responses/204",
+			want:    "{description: deleted}",
+		},
+		{
+			name:    "response headers",
+			swagger: "paths: {/pets: {get: {responses: {'200': {headers: {X-Rate-Limit: {type: integer, description: Requests left}}}}}}}",
+			pointer: "/paths/~1pets/get/responses/200",
+			want:    "{description: '', headers: {X-Rate-Limit: {description: Requests left, schema: {type: integer}}}}",
+		},
+		{
+			name:    "response component",
+			swagger: "responses: {Pet: {description: a pet, schema: {$ref: '#/definitions/Pet'}}}\npaths: {}",
+			pointer: "/components/responses/Pet",
+			want:    "{description: a pet, content: {application/json: {schema: {$ref: '#/components/schemas/Pet'}}}}",
+		},
+		{
+			name:    "x-nullable definition",
+			swagger: "definitions: {Pet: {type: string, x-nullable: true}}\npaths: {}",
+			pointer: "/components/schemas/Pet",
+			want:    "{type: [string, 'null']}",
+		},
+		{
+			name:    "x-nullable parameter",
+			swagger: "paths: {/pets: {get: {parameters: [{in: query, name: limit, type: integer, x-nullable: true}], " + ok + "}}}",
+			pointer: "/paths/~1pets/get/parameters/0/schema",
+			want:    "{type: [integer, 'null']}",
+		},
+		{
+			name:    "x-nullable reference",
+			swagger: "definitions: {Pet: {type: object, properties: {owner: {$ref: '#/definitions/Owner', x-nullable: true}}}, Owner: {type: string}}\npaths: {}",
+			pointer: "/components/schemas/Pet/properties/owner",
+			want:    "{anyOf: [{$ref: '#/components/schemas/Owner'}, {type: 'null'}]}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			swagger, err := parseDocument([]byte(header + test.swagger))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := convertSwagger20(swagger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			normalizeOpenApi30(doc)
+
+			got, err := resolvePointer(doc, test.pointer)
+			if err != nil {
+				t.Fatalf("%s in %s", err, valueJson(doc))
+			}
+
+			want := yamlValue(t, test.want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %s, want %s", valueJson(got), valueJson(want))
+			}
+		})
+	}
+}
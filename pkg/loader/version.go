@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SpecVersion int
+
+const (
+	SpecVersionUnknown SpecVersion = iota
+	SpecVersionSwagger20
+	SpecVersionOpenApi30
+	SpecVersionOpenApi31
+)
+
+func (v SpecVersion) String() string {
+	switch v {
+	case SpecVersionSwagger20:
+		return "Swagger 2.0"
+	case SpecVersionOpenApi30:
+		return "OpenAPI 3.0"
+	case SpecVersionOpenApi31:
+		return "OpenAPI 3.1"
+	default:
+		return "unknown"
+	}
+}
+
+// Detect the specification version from the top-level "openapi" (or "swagger") field
+func DetectVersion(doc map[string]any) (SpecVersion, error) {
+	if swagger, ok := doc["swagger"]; ok {
+		if versionString(swagger) == "2.0" {
+			return SpecVersionSwagger20, nil
+		}
+		return SpecVersionUnknown, fmt.Errorf("unsupported swagger version: %v", swagger)
+	}
+
+	openapi := versionString(doc["openapi"])
+	if openapi == "" {
+		return SpecVersionUnknown, fmt.Errorf("missing or invalid openapi version field: %v", doc["openapi"])
+	}
+
+	if strings.HasPrefix(openapi, "3.0.") || openapi == "3.0" {
+		return SpecVersionOpenApi30, nil
+	}
+
+	if strings.HasPrefix(openapi, "3.1.") || openapi == "3.1" {
+		return SpecVersionOpenApi31, nil
+	}
+
+	return SpecVersionUnknown, fmt.Errorf("unsupported openapi version: %s", openapi)
+}
+
+// An unquoted version in YAML, i.e. swagger: 2.0, is decoded as a number; "" if version isn't a string or number
+func versionString(version any) string {
+	switch v := version.(type) {
+	case string:
+		if v == "2" {
+			return "2.0"
+		}
+		return v
+	case float64, int, int64, uint64:
+		// fmt.Sprint(2.0) is 2
+		text := fmt.Sprint(v)
+		if !strings.Contains(text, ".") {
+			text += ".0"
+		}
+		return text
+	}
+	return ""
+}
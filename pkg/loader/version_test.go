@@ -0,0 +1,41 @@
+package loader
+
+import "testing"
+
+func TestDetectVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     map[string]any
+		want    SpecVersion
+		wantErr bool
+	}{
+		{name: "swagger string", doc: map[string]any{"swagger": "2.0"}, want: SpecVersionSwagger20},
+		{name: "swagger float", doc: map[string]any{"swagger": 2.0}, want: SpecVersionSwagger20},
+		{name: "swagger int", doc: map[string]any{"swagger": 2}, want: SpecVersionSwagger20},
+		{name: "swagger 1.2", doc: map[string]any{"swagger": "1.2"}, wantErr: true},
+		{name: "openapi 3.0.3", doc: map[string]any{"openapi": "3.0.3"}, want: SpecVersionOpenApi30},
+		{name: "openapi float 3.0", doc: map[string]any{"openapi": 3.0}, want: SpecVersionOpenApi30},
+		{name: "openapi 3.1.0", doc: map[string]any{"openapi": "3.1.0"}, want: SpecVersionOpenApi31},
+		{name: "openapi float 3.1", doc: map[string]any{"openapi": 3.1}, want: SpecVersionOpenApi31},
+		{name: "openapi 4.0", doc: map[string]any{"openapi": "4.0.0"}, wantErr: true},
+		{name: "missing", doc: map[string]any{}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version, err := DetectVersion(test.doc)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != test.want {
+				t.Errorf("version = %s, want %s", version, test.want)
+			}
+		})
+	}
+}
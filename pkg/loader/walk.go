@@ -0,0 +1,147 @@
+package loader
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Keywords whose value is a single subschema
+var subschemaKeywords = []string{
+	"additionalProperties", "items", "not", "if", "then", "else", "contains",
+	"propertyNames", "unevaluatedProperties", "unevaluatedItems",
+}
+
+// Keywords whose value is a list of subschemas
+var subschemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+
+// Keywords whose value is a map of name -> subschema
+var subschemaMapKeywords = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+
+// Call fn on every schema object reachable from the document (components, parameters, bodies, responses, headers).
+// fn is called on a schema before its subschemas, so it may rewrite the subschemas it is about to visit.
+func walkDocumentSchemas(doc map[string]any, fn func(schema map[string]any)) {
+	if components, ok := doc["components"].(map[string]any); ok {
+		walkComponents(components, fn)
+	}
+
+	if paths, ok := doc["paths"].(map[string]any); ok {
+		for _, pathItem := range paths {
+			walkPathItem(pathItem, fn)
+		}
+	}
+
+	if webhooks, ok := doc["webhooks"].(map[string]any); ok {
+		for _, pathItem := range webhooks {
+			walkPathItem(pathItem, fn)
+		}
+	}
+}
+
+func walkComponents(components map[string]any, fn func(schema map[string]any)) {
+	for _, schema := range asMap(components["schemas"]) {
+		walkSchema(schema, fn)
+	}
+
+	for _, parameter := range asMap(components["parameters"]) {
+		walkParameter(parameter, fn)
+	}
+
+	for _, requestBody := range asMap(components["requestBodies"]) {
+		walkContent(asMap(requestBody)["content"], fn)
+	}
+
+	for _, response := range asMap(components["responses"]) {
+		walkResponse(response, fn)
+	}
+
+	for _, header := range asMap(components["headers"]) {
+		walkParameter(header, fn)
+	}
+
+	for _, pathItem := range asMap(components["pathItems"]) {
+		walkPathItem(pathItem, fn)
+	}
+}
+
+func walkPathItem(pathItem any, fn func(schema map[string]any)) {
+	item := asMap(pathItem)
+	for _, parameter := range asSlice(item["parameters"]) {
+		walkParameter(parameter, fn)
+	}
+
+	for _, method := range httpMethods {
+		operation := asMap(item[method])
+		if operation == nil {
+			continue
+		}
+
+		for _, parameter := range asSlice(operation["parameters"]) {
+			walkParameter(parameter, fn)
+		}
+
+		if requestBody := asMap(operation["requestBody"]); requestBody != nil {
+			walkContent(requestBody["content"], fn)
+		}
+
+		for _, response := range asMap(operation["responses"]) {
+			walkResponse(response, fn)
+		}
+	}
+}
+
+// Parameters and headers share the same shape: either a schema or a content map
+func walkParameter(parameter any, fn func(schema map[string]any)) {
+	param := asMap(parameter)
+	if schema, ok := param["schema"]; ok {
+		walkSchema(schema, fn)
+	}
+	walkContent(param["content"], fn)
+}
+
+func walkResponse(response any, fn func(schema map[string]any)) {
+	resp := asMap(response)
+	walkContent(resp["content"], fn)
+	for _, header := range asMap(resp["headers"]) {
+		walkParameter(header, fn)
+	}
+}
+
+func walkContent(content any, fn func(schema map[string]any)) {
+	for _, mediaType := range asMap(content) {
+		if schema, ok := asMap(mediaType)["schema"]; ok {
+			walkSchema(schema, fn)
+		}
+	}
+}
+
+func walkSchema(node any, fn func(schema map[string]any)) {
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+
+	fn(schema)
+
+	for _, keyword := range subschemaKeywords {
+		walkSchema(schema[keyword], fn)
+	}
+
+	for _, keyword := range subschemaListKeywords {
+		for _, subschema := range asSlice(schema[keyword]) {
+			walkSchema(subschema, fn)
+		}
+	}
+
+	for _, keyword := range subschemaMapKeywords {
+		for _, subschema := range asMap(schema[keyword]) {
+			walkSchema(subschema, fn)
+		}
+	}
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
@@ -3,89 +3,61 @@ package typedfetch
 import (
 	"fmt"
 	"strings"
-
-	"github.com/swaggest/openapi-go/openapi31"
 )
 
-func generateClient(reflector *openapi31.Reflector) ([]string, error) {
-	clientInterfaceLookups := map[string][]string{}
-
-	sortedPaths := sortedMapKeys(reflector.Spec.Paths.MapOfPathItemValues)
-	for _, path := range sortedPaths {
-		item := reflector.Spec.Paths.MapOfPathItemValues[path]
-		methods := getPathItemMethods(&item)
-		for _, method := range methods {
-			if method.Operation == nil {
-				continue
-			}
-
-			// Generate the param type
-			paramInfo, err := getParamInfo(reflector, method.Operation, method.Method, path)
-			if err != nil {
-				return nil, err
-			}
-
-			// Generate the body type
-			bodyInfo, err := getRequestBodyInfo(reflector, method.Operation, method.Method, path)
-			if err != nil {
-				return nil, err
-			}
-
-			// Generate the client interface
-			initRequired := paramInfo.Required || bodyInfo.Required
-			requestTypeName := getRequestTypeName(method.Method, path)
-			responseDataTypeName := getResponseDataTypeName(method.Method, path)
-			responseErrTypeName := getResponseErrTypeName(method.Method, path)
-
-			if _, ok := clientInterfaceLookups[method.Method]; !ok {
-				clientInterfaceLookups[method.Method] = []string{}
-			}
-
-			initOptionalQ := "?"
-			if initRequired {
-				initOptionalQ = ""
-			}
-
-			lookupLine := fmt.Sprintf("\"%s\": {init%s: %s, response: FetchResponse<%s, %s>}",
-				path,
-				initOptionalQ,
-				requestTypeName,
-				responseDataTypeName,
-				responseErrTypeName,
-			)
-
-			clientInterfaceLookups[method.Method] = append(clientInterfaceLookups[method.Method], lookupLine)
+func (p *tsPrinter) printClient(operations []*Operation) []string {
+	clientInterfaceLookups := map[string]*tsObject{}
+
+	for _, op := range operations {
+		if op.Failed {
+			continue
 		}
+
+		initRequired := (op.Params != nil && op.Params.Required) || (op.Body != nil && op.Body.Required)
+		lookup := &tsObject{inline: true, members: []*tsMember{
+			{name: "init", optional: !initRequired, typ: tsReferenceTo(op.RequestTypeName)},
+			{name: "response", typ: tsReferenceTo("FetchResponse", tsReferenceTo(op.ResponseData.TypeName), tsReferenceTo(op.ResponseError.TypeName))},
+		}}
+
+		if _, ok := clientInterfaceLookups[op.Method]; !ok {
+			clientInterfaceLookups[op.Method] = &tsObject{}
+		}
+		clientInterfaceLookups[op.Method].members = append(clientInterfaceLookups[op.Method].members, &tsMember{name: op.Path, typ: lookup})
 	}
 
+	lines := []string{p.reindent(p.exportTemplate(responseGenerics)), ""}
+
 	// Generate the client interface
-	typeLookupLines := []string{}
-	clientInterfaceLines := []string{}
-	for method, lines := range clientInterfaceLookups {
+	lines = append(lines,
+		"// Generics Type Lookups",
+		"// These are lookup tables for each method type (GET, POST, etc) to match the url to its payload",
+		"",
+	)
+	client := &tsObject{}
+	for _, method := range sortedMapKeys(clientInterfaceLookups) {
 		typeLookupTypeName := getLookupTypeName(method)
-		typeLookupLines = append(typeLookupLines, fmt.Sprintf("type %s = {\n    %s\n};", typeLookupTypeName, strings.Join(lines, ",\n    ")))
-		typeLookupLines = append(typeLookupLines, "")
-
-		clientInterfaceLines = append(clientInterfaceLines,
-			fmt.Sprintf(`%s: ClientMethod<%s>;`,
-				method,
-				typeLookupTypeName,
-			))
+		lines = append(lines, p.typeAlias("", typeLookupTypeName, clientInterfaceLookups[method])...)
+		lines = append(lines, "")
+
+		client.members = append(client.members, &tsMember{name: method, typ: tsReferenceTo("ClientMethod", tsReferenceTo(typeLookupTypeName))})
 	}
 
-	lines := []string{
-		strings.TrimSpace(fmt.Sprintf(`
-// Response Generics
+	lines = append(lines, p.reindent(p.exportTemplate(clientMethodMagic)), "")
+	lines = append(lines, "export interface Client "+p.printObject(client, 0, 0))
 
-type DataResponse<D> = { data: D; error: undefined; response: Response; };
-type ErrorResponse<E> = { data: undefined; error: E; response: Response; };
-type FetchResponse<D, E> = DataResponse<D> | ErrorResponse<E>;
+	return lines
+}
 
-// Generics Type Lookups
-// These are lookup tables for each method type (GET, POST, etc) to match the url to its payload
+// {export} is replaced by "export " if declarations are exported
+const responseGenerics = `
+// Response Generics
 
-%s
+{export}type DataResponse<D> = { data: D; error: undefined; response: Response; };
+{export}type ErrorResponse<E> = { data: undefined; error: E; response: Response; };
+{export}type FetchResponse<D, E> = DataResponse<D> | ErrorResponse<E>;
+`
 
+const clientMethodMagic = `
 /* 
     We could just generate a bunch of overloaded functions in the interface like:
 
@@ -112,24 +84,35 @@ type FetchResponse<D, E> = DataResponse<D> | ErrorResponse<E>;
 
 // https://stackoverflow.com/questions/52984808/is-there-a-way-to-get-all-required-properties-of-a-typescript-object
 // Example: OptionalKeys<{a: string, b?: number}> = "b"
-type OptionalKeys<T extends object> = keyof { [K in keyof T as {} extends Pick<T, K> ? K : never]: any }
+{export}type OptionalKeys<T extends object> = keyof { [K in keyof T as {} extends Pick<T, K> ? K : never]: any }
 
 // https://stackoverflow.com/questions/77714794/how-to-use-void-to-make-function-parameters-optional-when-using-generics
 // Basically, this is a way to achieve i.e. GET(url, init?) if init is optional, and GET(url, init) if init is required
-type ClientMethod<Lookup extends Record<string, any>> = <Url extends keyof Lookup>(
+{export}type ClientMethod<Lookup extends Record<string, any>> = <Url extends keyof Lookup>(
     url: Url,
     ...[init]: "init" extends OptionalKeys<Lookup[Url]> ? [init?: Lookup[Url]["init"]] : [init: Lookup[Url]["init"]]
 ) => Lookup[Url]["response"];
 
 // end evil TypeScript magic
+`
 
-export interface Client {
-    %s
+func (p *tsPrinter) exportTemplate(template string) string {
+	return strings.ReplaceAll(strings.TrimSpace(template), "{export}", p.export())
 }
-`, strings.Join(typeLookupLines, "\n"), strings.Join(clientInterfaceLines, "\n    "))),
-	}
 
-	return lines, nil
+// Names of the types printClient declares
+func clientTypeNames(operations []*Operation) []string {
+	names := []string{"DataResponse", "ErrorResponse", "FetchResponse", "OptionalKeys", "ClientMethod", "Client"}
+	methods := map[string]bool{}
+	for _, op := range operations {
+		if !op.Failed {
+			methods[op.Method] = true
+		}
+	}
+	for _, method := range sortedMapKeys(methods) {
+		names = append(names, getLookupTypeName(method))
+	}
+	return names
 }
 
 func getLookupTypeName(method string) string {
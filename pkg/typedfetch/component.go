@@ -1,75 +1,125 @@
 package typedfetch
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
-func generateComponentSchemaTypes(reflector *openapi31.Reflector) ([]string, error) {
+func (g *generator) buildComponents() ([]*NamedType, error) {
+	reflector := g.reflector
+
+	// Skip the components no (selected) operation uses
+	var reachable map[string]bool
+	if g.options.TreeShake || g.isFiltering() {
+		var err error
+		reachable, err = g.reachableComponentSchemas()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	components := []*NamedType{}
+	sortedComponents := sortedMapKeys(specComponents(reflector).Schemas)
+	for _, component := range sortedComponents {
+		if reachable != nil && !reachable[component] {
+			continue
+		}
+
+		item := specComponents(reflector).Schemas[component]
+		namedType := &NamedType{
+			Name:        g.componentSchemaTypeName(component),
+			SchemaName:  component,
+			Pointer:     jsonPointer("components", "schemas", component),
+			Description: getDescription(item),
+			Example:     getExample(item),
+		}
+
+		g.owner = hoistOwner{name: namedType.Name, pointer: namedType.Pointer}
+		t, err := g.schemaToType(item, namedType.Pointer)
+		if err != nil && !g.collect(err) {
+			return nil, err
+		}
+
+		// A component that failed is kept (without a type) so that anything referencing it still type checks
+		namedType.Type = t
+		components = append(components, namedType)
+	}
+
+	return components, nil
+}
+
+func (p *tsPrinter) printComponentTypes(components []*NamedType) []string {
 	lines := []string{
 		"// Component types",
 		"",
 	}
 
-	// For each explicit component, generate the type
-	sortedComponents := sortedMapKeys(reflector.Spec.Components.Schemas)
-	for _, component := range sortedComponents {
-		item := reflector.Spec.Components.Schemas[component]
-		componentName := getComponentSchemaTypeName(component)
-		typeDecl, err := jsonTypeToTypescriptType(item)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %v", componentName, err)
+	for _, component := range components {
+		if component.Type == nil {
+			lines = append(lines, p.typeAlias("/** typed-fetch: this type could not be generated, see errors */", component.Name, &tsKeyword{"unknown"})...)
+			lines = append(lines, "")
+			continue
 		}
 
-		docString := getDocString(item)
-		if docString != "" {
-			lines = append(lines, docString)
-		}
-		lines = append(lines, fmt.Sprintf("type %s = %s", componentName, typeDecl))
+		docString := buildDocString(component.Description, component.Example)
+		lines = append(lines, p.printNamedType(docString, component.Name, component.Type)...)
 		lines = append(lines, "")
 	}
 
-	return lines, nil
+	return lines
 }
 
-func resolveRefParameter(ref string, reflector *openapi31.Reflector) (*openapi31.Parameter, error) {
+func namedTypeNames(types []*NamedType) []string {
+	names := []string{}
+	for _, t := range types {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// type Name = T;, or the const object and type of an enum
+func (p *tsPrinter) printNamedType(doc, name string, t Type) []string {
+	if enum, ok := t.(*EnumType); ok {
+		return p.printEnumObject(doc, name, enum)
+	}
+	return p.typeAlias(doc, name, toTsType(t))
+}
+
+// Returns the parameter along with its JSON Pointer in the document
+func resolveRefParameter(ref string, reflector *openapi31.Reflector) (*openapi31.Parameter, string, error) {
 	if !strings.HasPrefix(ref, "#/components/parameters/") {
-		return nil, fmt.Errorf("reference %s is not a parameter", ref)
+		return nil, "", specErrorf("reference %s is not a parameter", ref)
 	}
 
 	parameterName := strings.TrimPrefix(ref, "#/components/parameters/")
-	parameterOrReference, ok := reflector.Spec.Components.Parameters[parameterName]
+	parameterOrReference, ok := specComponents(reflector).Parameters[parameterName]
 	if !ok {
-		return nil, fmt.Errorf("parameter %s not found", parameterName)
+		return nil, "", specErrorf("parameter %s not found", parameterName)
 	}
 
 	if parameterOrReference.Reference != nil {
 		return resolveRefParameter(parameterOrReference.Reference.Ref, reflector)
 	}
 
-	return parameterOrReference.Parameter, nil
+	return parameterOrReference.Parameter, refToPointer(ref), nil
 }
 
-func resolveRefRequestBody(ref string, reflector *openapi31.Reflector) (*openapi31.RequestBody, error) {
+// Returns the request body along with its JSON Pointer in the document
+func resolveRefRequestBody(ref string, reflector *openapi31.Reflector) (*openapi31.RequestBody, string, error) {
 	if !strings.HasPrefix(ref, "#/components/requestBodies/") {
-		return nil, fmt.Errorf("reference %s is not a requestBody", ref)
+		return nil, "", specErrorf("reference %s is not a requestBody", ref)
 	}
 
 	requestBodyName := strings.TrimPrefix(ref, "#/components/requestBodies/")
-	requestBodyOrReference, ok := reflector.Spec.Components.RequestBodies[requestBodyName]
+	requestBodyOrReference, ok := specComponents(reflector).RequestBodies[requestBodyName]
 	if !ok {
-		return nil, fmt.Errorf("requestBody %s not found", requestBodyName)
+		return nil, "", specErrorf("requestBody %s not found", requestBodyName)
 	}
 
 	if requestBodyOrReference.Reference != nil {
 		return resolveRefRequestBody(requestBodyOrReference.Reference.Ref, reflector)
 	}
 
-	return requestBodyOrReference.RequestBody, nil
-}
-
-func getComponentSchemaTypeName(name string) string {
-	return fmt.Sprintf("ComponentSchema%s", capitalize(name))
+	return requestBodyOrReference.RequestBody, refToPointer(ref), nil
 }
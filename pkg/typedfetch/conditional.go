@@ -0,0 +1,413 @@
+package typedfetch
+
+import (
+	"fmt"
+)
+
+// Best-effort translation of the JSON Schema conditional keywords, which TypeScript can only express as a union
+// of the object types allowed in each case:
+//
+//	if/then/else on const properties (a discriminator) -> the object with the if properties and then | the object with the
+//	                                                      other values and else
+//	dependentRequired: {a: [b]}                         -> the object without a | the object with a and b required
+//	dependentSchemas: {a: S}                            -> the object without a | the object with a required and S
+//
+// Anything else is left out with a warning, see withoutUnsupportedKeywords.
+
+var conditionalKeywords = []string{"if", "then", "else", "dependentRequired", "dependentSchemas"}
+
+// Each dependency doubles the variants, so past this many the object is generated without its dependencies
+const maxConditionalVariants = 32
+
+// One case of an object with conditional keywords: the object type plus the types it's intersected with
+type objectVariant struct {
+	object *ObjectType
+	extras []Type
+}
+
+// Remove the keywords the translation doesn't support from schema, warning about each: not, which TypeScript can't
+// express, and the conditional keywords of anything but an object
+func (g *generator) withoutUnsupportedKeywords(schema map[string]any, pointer string) map[string]any {
+	unsupported := []string{}
+	if _, ok := schema["not"]; ok {
+		unsupported = append(unsupported, "not")
+	}
+
+	isObject := schema["type"] == "object"
+	if types, ok := schema["type"].([]any); ok {
+		// The other members of a type list leave the conditional keywords out, see typeListToType
+		isObject = itemInSlice(types, any("object"))
+	}
+
+	if !isObject {
+		for _, keyword := range conditionalKeywords {
+			if _, ok := schema[keyword]; ok {
+				unsupported = append(unsupported, keyword)
+			}
+		}
+	}
+
+	if len(unsupported) == 0 {
+		return schema
+	}
+
+	schema = copySchema(schema)
+	for _, keyword := range unsupported {
+		delete(schema, keyword)
+		if keyword == "not" {
+			g.warn(&SpecError{Pointer: pointer + jsonPointer(keyword), Message: "not is not supported, generating the type without it"})
+		} else {
+			g.warn(&SpecError{Pointer: pointer + jsonPointer(keyword), Message: keyword + " is only supported on objects, generating the type without it"})
+		}
+	}
+	return schema
+}
+
+// The union of the variants of object allowed by its if/then/else, dependentRequired and dependentSchemas;
+// object itself if it has none
+func (g *generator) applyConditionals(object *ObjectType, schema map[string]any, pointer string) (Type, error) {
+	variants := []*objectVariant{{object: object}}
+
+	if _, ok := schema["if"]; ok {
+		var err error
+		variants, err = g.ifThenElseVariants(object, schema, pointer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dependentRequired, ok := schema["dependentRequired"]; ok {
+		dependentRequiredPointer := pointer + jsonPointer("dependentRequired")
+		dependencies, ok := dependentRequired.(map[string]any)
+		if !ok {
+			return nil, specErrorAt(dependentRequiredPointer, "invalid dependentRequired: %v", dependentRequired)
+		}
+
+		for _, property := range sortedMapKeys(dependencies) {
+			var err error
+			branch := map[string]any{"required": dependencies[property]}
+			variants, err = g.dependentVariants(variants, property, branch, dependentRequiredPointer+jsonPointer(property))
+			if err != nil {
+				return nil, err
+			}
+			if len(variants) > maxConditionalVariants {
+				return g.tooManyVariants(object, dependentRequiredPointer), nil
+			}
+		}
+	}
+
+	if dependentSchemas, ok := schema["dependentSchemas"]; ok {
+		dependentSchemasPointer := pointer + jsonPointer("dependentSchemas")
+		dependencies, ok := dependentSchemas.(map[string]any)
+		if !ok {
+			return nil, specErrorAt(dependentSchemasPointer, "invalid dependentSchemas: %v", dependentSchemas)
+		}
+
+		for _, property := range sortedMapKeys(dependencies) {
+			branch, ok := dependencies[property].(map[string]any)
+			if !ok {
+				return nil, specErrorAt(dependentSchemasPointer+jsonPointer(property), "invalid schema: %v", dependencies[property])
+			}
+
+			var err error
+			variants, err = g.dependentVariants(variants, property, branch, dependentSchemasPointer+jsonPointer(property))
+			if err != nil {
+				return nil, err
+			}
+			if len(variants) > maxConditionalVariants {
+				return g.tooManyVariants(object, dependentSchemasPointer), nil
+			}
+		}
+	}
+
+	if len(variants) == 1 {
+		return variants[0].toType(), nil
+	}
+
+	union := &UnionType{}
+	for _, variant := range variants {
+		union.Members = append(union.Members, variant.toType())
+	}
+	return union, nil
+}
+
+// object without its conditional keywords, with a warning
+func (g *generator) tooManyVariants(object *ObjectType, pointer string) Type {
+	message := fmt.Sprintf("the dependencies allow more than %d variants of the object, generating the type without if/then/else and dependencies", maxConditionalVariants)
+	g.warn(&SpecError{Pointer: pointer, Message: message})
+	return object
+}
+
+// The then and else variants of object, if the if schema only has const properties, i.e.
+// if: {properties: {kind: {const: dog}}}. Otherwise object itself, with a warning.
+func (g *generator) ifThenElseVariants(object *ObjectType, schema map[string]any, pointer string) ([]*objectVariant, error) {
+	ifPointer := pointer + jsonPointer("if")
+	discriminators, required, ok := constProperties(schema["if"])
+	if !ok {
+		g.warn(&SpecError{Pointer: ifPointer, Message: "if is only supported with const properties, generating the type without if/then/else"})
+		return []*objectVariant{{object: object}}, nil
+	}
+
+	thenVariant := &objectVariant{object: cloneObjectType(object)}
+	elseVariant := &objectVariant{object: cloneObjectType(object)}
+	for _, name := range sortedMapKeys(discriminators) {
+		value := discriminators[name]
+
+		// The if schema holds if the property is missing, unless it's required
+		thenProperty := thenVariant.property(name)
+		thenProperty.Type = &LiteralType{Value: value}
+		thenProperty.Optional = thenProperty.Optional && !itemInSlice(required, name)
+
+		elseProperty := elseVariant.property(name)
+		elseProperty.Type = excludeValue(elseProperty.Type, value)
+		elseProperty.Optional = elseProperty.Optional && itemInSlice(required, name)
+	}
+
+	errs := SpecErrors{}
+	for _, branch := range []struct {
+		keyword string
+		variant *objectVariant
+	}{{"then", thenVariant}, {"else", elseVariant}} {
+		branchSchema, ok := schema[branch.keyword]
+		if !ok {
+			continue
+		}
+
+		branchPointer := pointer + jsonPointer(branch.keyword)
+		branchMap, ok := branchSchema.(map[string]any)
+		if !ok {
+			errs = errs.append(specErrorAt(branchPointer, "invalid schema: %v", branchSchema))
+			continue
+		}
+
+		err := g.applyBranch(branch.variant, branchMap, branchPointer)
+		if err != nil {
+			errs = errs.append(err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return []*objectVariant{thenVariant, elseVariant}, nil
+}
+
+// Split each variant in two: without property, and with property required and branch applied. A variant where the
+// property is required already only keeps the latter.
+func (g *generator) dependentVariants(variants []*objectVariant, property string, branch map[string]any, pointer string) ([]*objectVariant, error) {
+	split := []*objectVariant{}
+	for _, variant := range variants {
+		existing := variant.findProperty(property)
+		if existing == nil || existing.Optional {
+			without := variant.clone()
+			withoutProperty := without.property(property)
+			withoutProperty.Type = &KeywordType{Keyword: "never"}
+			split = append(split, without)
+		}
+
+		with := variant.clone()
+		with.property(property).Optional = false
+		err := g.applyBranch(with, branch, pointer)
+		if err != nil {
+			return nil, err
+		}
+		split = append(split, with)
+	}
+	return split, nil
+}
+
+// Apply the properties and required properties of a then/else/dependent schema to variant. A $ref or composition
+// in the schema is intersected with it.
+func (g *generator) applyBranch(variant *objectVariant, branch map[string]any, pointer string) error {
+	required, err := getRequiredProps(branch)
+	if err != nil {
+		return wrapSpecError(err, pointer)
+	}
+
+	errs := SpecErrors{}
+	properties, _ := branch["properties"].(map[string]any)
+	for _, name := range sortedMapKeys(properties) {
+		propPointer := pointer + jsonPointer("properties", name)
+		propSchema, ok := properties[name].(map[string]any)
+		if !ok {
+			errs = errs.append(specErrorAt(propPointer, "invalid property schema: %v", properties[name]))
+			continue
+		}
+
+		propType, err := g.schemaToType(propSchema, propPointer)
+		if err != nil {
+			errs = errs.append(err)
+			continue
+		}
+
+		property := variant.property(name)
+		property.Type = propType
+		property.Description = getDescription(propSchema)
+		property.Example = getExample(propSchema)
+	}
+
+	for _, name := range required {
+		variant.property(name).Optional = false
+	}
+
+	_, hasRef := branch["$ref"]
+	if hasRef || isComposedSchema(branch) {
+		rest := copySchema(branch)
+		delete(rest, "properties")
+		delete(rest, "required")
+		extra, err := g.schemaToType(rest, pointer)
+		if err != nil {
+			errs = errs.append(err)
+		} else {
+			variant.extras = append(variant.extras, extra)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// The const values of the properties of an if schema, and its required properties; false if it has anything else
+func constProperties(ifSchema any) (map[string]any, []string, bool) {
+	schema, ok := ifSchema.(map[string]any)
+	if !ok {
+		return nil, nil, false
+	}
+
+	for keyword, value := range schema {
+		if keyword != "properties" && keyword != "required" && !(keyword == "type" && value == "object") {
+			return nil, nil, false
+		}
+	}
+
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok || len(properties) == 0 {
+		return nil, nil, false
+	}
+
+	values := map[string]any{}
+	for name, property := range properties {
+		propSchema, ok := property.(map[string]any)
+		if !ok {
+			return nil, nil, false
+		}
+
+		if value, ok := propSchema["const"]; ok {
+			values[name] = value
+		} else if enum, ok := propSchema["enum"].([]any); ok && len(enum) == 1 {
+			values[name] = enum[0]
+		} else {
+			return nil, nil, false
+		}
+
+		switch values[name].(type) {
+		case string, float64, int, int64, uint64, bool:
+		default:
+			return nil, nil, false
+		}
+	}
+
+	required, err := getRequiredProps(schema)
+	if err != nil {
+		return nil, nil, false
+	}
+	return values, required, true
+}
+
+// t without the literal value, i.e. ('dog' | 'cat', 'dog') -> 'cat' and (boolean | null, true) -> false | null,
+// or Exclude<T, 'dog'> for any other union. Any other type is returned as is, since excluding a literal from i.e.
+// string leaves string. nil for a property the object doesn't declare.
+func excludeValue(t Type, value any) Type {
+	if t == nil {
+		return nil
+	}
+
+	if union, ok := t.(*UnionType); ok {
+		rest := &UnionType{}
+		for _, member := range union.Members {
+			if isBooleanKeyword(member) {
+				member = excludeValue(member, value)
+			}
+
+			// null is only excluded by const: null
+			if keyword, ok := member.(*KeywordType); ok && keyword.Keyword == "null" {
+				if value != nil {
+					rest.Members = append(rest.Members, member)
+				}
+				continue
+			}
+
+			literal, ok := member.(*LiteralType)
+			if !ok {
+				return &ReferenceType{Name: "Exclude", TypeArguments: []Type{t, &LiteralType{Value: value}}}
+			}
+			if fmt.Sprint(literal.Value) != fmt.Sprint(value) {
+				rest.Members = append(rest.Members, literal)
+			}
+		}
+		return rest
+	}
+
+	// boolean is true | false
+	if boolean, ok := value.(bool); ok && isBooleanKeyword(t) {
+		return &LiteralType{Value: !boolean}
+	}
+
+	return t
+}
+
+func isBooleanKeyword(t Type) bool {
+	keyword, ok := t.(*KeywordType)
+	return ok && keyword.Keyword == "boolean"
+}
+
+func (v *objectVariant) clone() *objectVariant {
+	return &objectVariant{object: cloneObjectType(v.object), extras: append([]Type{}, v.extras...)}
+}
+
+func (v *objectVariant) findProperty(name string) *Property {
+	for _, property := range v.object.Properties {
+		if property.Name == name {
+			return property
+		}
+	}
+	return nil
+}
+
+// The property name of the variant, added (optional, without a type) if the object doesn't declare it
+func (v *objectVariant) property(name string) *Property {
+	if property := v.findProperty(name); property != nil {
+		return property
+	}
+
+	property := &Property{Name: name, Optional: true}
+	v.object.Properties = append(v.object.Properties, property)
+	return property
+}
+
+func (v *objectVariant) toType() Type {
+	for _, property := range v.object.Properties {
+		if property.Type == nil {
+			// Only required by a branch, without a schema of its own
+			property.Type = &KeywordType{Keyword: "any"}
+		}
+	}
+
+	if len(v.extras) == 0 {
+		return v.object
+	}
+	return &IntersectionType{Members: append([]Type{v.object}, v.extras...)}
+}
+
+// A copy of object whose properties can be changed without changing object
+func cloneObjectType(object *ObjectType) *ObjectType {
+	clone := *object
+	clone.Properties = []*Property{}
+	for _, property := range object.Properties {
+		copied := *property
+		clone.Properties = append(clone.Properties, &copied)
+	}
+	return &clone
+}
@@ -0,0 +1,153 @@
+package typedfetch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConditionalKeywords(t *testing.T) {
+	anyType := &KeywordType{Keyword: "any"}
+	stringType := &KeywordType{Keyword: "string"}
+
+	tests := []struct {
+		name    string
+		schemas string
+
+		// Type of the component A
+		want Type
+	}{
+		{
+			name: "if then else",
+			schemas: "{A: {type: object, properties: {kind: {type: string, enum: [dog, cat]}}," +
+				" if: {properties: {kind: {const: dog}}}, then: {required: [bark]}}}",
+			want: &UnionType{Members: []Type{
+				&ObjectType{Properties: []*Property{
+					{Name: "kind", Optional: true, Type: &LiteralType{Value: "dog"}},
+					{Name: "bark", Type: anyType},
+				}},
+				&ObjectType{Properties: []*Property{
+					{Name: "kind", Type: &UnionType{Members: []Type{&LiteralType{Value: "cat"}}}},
+				}},
+			}},
+		},
+		{
+			name: "else",
+			schemas: "{A: {type: object, properties: {kind: {type: string}}," +
+				" if: {properties: {kind: {const: dog}}}, then: {required: [bark]}, else: {required: [meow]}}}",
+			want: &UnionType{Members: []Type{
+				&ObjectType{Properties: []*Property{
+					{Name: "kind", Optional: true, Type: &LiteralType{Value: "dog"}},
+					{Name: "bark", Type: anyType},
+				}},
+				&ObjectType{Properties: []*Property{
+					{Name: "kind", Type: stringType},
+					{Name: "meow", Type: anyType},
+				}},
+			}},
+		},
+		{
+			name: "boolean",
+			schemas: "{A: {type: object, properties: {paid: {type: boolean}}," +
+				" if: {properties: {paid: {const: true}}, required: [paid]}, then: {required: [receipt]}}}",
+			want: &UnionType{Members: []Type{
+				&ObjectType{Properties: []*Property{
+					{Name: "paid", Type: &LiteralType{Value: true}},
+					{Name: "receipt", Type: anyType},
+				}},
+				&ObjectType{Properties: []*Property{
+					{Name: "paid", Optional: true, Type: &LiteralType{Value: false}},
+				}},
+			}},
+		},
+		{
+			name: "nullable boolean",
+			schemas: "{A: {type: object, properties: {paid: {type: [boolean, 'null']}}," +
+				" if: {properties: {paid: {const: false}}}, then: {required: [reason]}}}",
+			want: &UnionType{Members: []Type{
+				&ObjectType{Properties: []*Property{
+					{Name: "paid", Optional: true, Type: &LiteralType{Value: false}},
+					{Name: "reason", Type: anyType},
+				}},
+				&ObjectType{Properties: []*Property{
+					{Name: "paid", Type: &UnionType{Members: []Type{&LiteralType{Value: true}, &KeywordType{Keyword: "null"}}}},
+				}},
+			}},
+		},
+		{
+			name:    "dependent required",
+			schemas: "{A: {type: object, properties: {a: {type: string}, b: {type: string}}, dependentRequired: {a: [b]}}}",
+			want: &UnionType{Members: []Type{
+				&ObjectType{Properties: []*Property{
+					{Name: "a", Optional: true, Type: &KeywordType{Keyword: "never"}},
+					{Name: "b", Optional: true, Type: stringType},
+				}},
+				&ObjectType{Properties: []*Property{
+					{Name: "a", Type: stringType},
+					{Name: "b", Type: stringType},
+				}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, test.schemas, "", Options{})
+
+			component := testComponent(t, model, "A")
+			if !reflect.DeepEqual(component.Type, test.want) {
+				t.Errorf("type = %s, want %s", typeJson(component.Type), typeJson(test.want))
+			}
+		})
+	}
+}
+
+func TestExcludeValue(t *testing.T) {
+	booleanType := &KeywordType{Keyword: "boolean"}
+	nullType := &KeywordType{Keyword: "null"}
+	stringType := &KeywordType{Keyword: "string"}
+	dogOrCat := &UnionType{Members: []Type{&LiteralType{Value: "dog"}, &LiteralType{Value: "cat"}}}
+
+	tests := []struct {
+		name  string
+		typ   Type
+		value any
+		want  Type
+	}{
+		{name: "undeclared", typ: nil, value: "dog", want: nil},
+		{name: "literals", typ: dogOrCat, value: "dog", want: &UnionType{Members: []Type{&LiteralType{Value: "cat"}}}},
+		{name: "keyword", typ: stringType, value: "dog", want: stringType},
+		{name: "true", typ: booleanType, value: true, want: &LiteralType{Value: false}},
+		{name: "false", typ: booleanType, value: false, want: &LiteralType{Value: true}},
+		{name: "boolean and a string", typ: booleanType, value: "true", want: booleanType},
+		{
+			name:  "nullable boolean",
+			typ:   &UnionType{Members: []Type{booleanType, nullType}},
+			value: true,
+			want:  &UnionType{Members: []Type{&LiteralType{Value: false}, nullType}},
+		},
+		{
+			name:  "null",
+			typ:   &UnionType{Members: []Type{&LiteralType{Value: "dog"}, nullType}},
+			value: nil,
+			want:  &UnionType{Members: []Type{&LiteralType{Value: "dog"}}},
+		},
+		{
+			name:  "other union",
+			typ:   &UnionType{Members: []Type{stringType, &LiteralType{Value: 1}}},
+			value: 1,
+			want: &ReferenceType{Name: "Exclude", TypeArguments: []Type{
+				&UnionType{Members: []Type{stringType, &LiteralType{Value: 1}}},
+				&LiteralType{Value: 1},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := excludeValue(test.typ, test.value)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %s, want %s", typeJson(got), typeJson(test.want))
+			}
+		})
+	}
+}
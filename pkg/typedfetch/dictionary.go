@@ -0,0 +1,133 @@
+package typedfetch
+
+import (
+	"regexp"
+)
+
+// A pattern matching a literal prefix, i.e. ^x- or ^x-.*$ (but not ^x-[a-z]+)
+var prefixPatternRegexp = regexp.MustCompile(`^\^((?:[\w\-/:@ ]|\\[^\w\s])+)(?:\.[*+]\$?)?$`)
+
+var regexpEscapeRegexp = regexp.MustCompile(`\\(.)`)
+
+// patternProperties with a literal prefix become template literal index signatures, i.e. [key: `x-${string}`]: T.
+// TypeScript can't express any other pattern, so their types are added to the string index signature instead,
+// along with the prefixed types if there is one.
+func (g *generator) addPatternProperties(object *ObjectType, schema map[string]any, pointer string) error {
+	patternProps, ok := schema["patternProperties"]
+	if !ok {
+		return nil
+	}
+
+	patternPropsPointer := pointer + jsonPointer("patternProperties")
+	patterns, ok := patternProps.(map[string]any)
+	if !ok {
+		return specErrorAt(patternPropsPointer, "invalid patternProperties: %v", patternProps)
+	}
+
+	indexTypes := []Type{}
+	if object.IndexSignature != nil {
+		indexTypes = append(indexTypes, object.IndexSignature)
+	}
+
+	errs := SpecErrors{}
+	for _, pattern := range sortedMapKeys(patterns) {
+		patternPointer := patternPropsPointer + jsonPointer(pattern)
+		patternSchema, ok := patterns[pattern].(map[string]any)
+		if !ok {
+			errs = errs.append(specErrorAt(patternPointer, "invalid property schema: %v", patterns[pattern]))
+			continue
+		}
+
+		patternType, err := g.schemaToType(patternSchema, patternPointer)
+		if err != nil {
+			errs = errs.append(err)
+			continue
+		}
+
+		if match := prefixPatternRegexp.FindStringSubmatch(pattern); match != nil {
+			prefix := regexpEscapeRegexp.ReplaceAllString(match[1], "$1")
+			object.PrefixSignatures = append(object.PrefixSignatures, &PrefixSignature{Prefix: prefix, Type: patternType})
+		} else {
+			indexTypes = append(indexTypes, patternType)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// The names matching a prefix match the string index signature too, so it must allow their types
+	if len(indexTypes) > 0 {
+		for _, signature := range object.PrefixSignatures {
+			indexTypes = append(indexTypes, signature.Type)
+		}
+	}
+
+	if len(indexTypes) == 1 {
+		object.IndexSignature = indexTypes[0]
+	} else if len(indexTypes) > 1 {
+		object.IndexSignature = &UnionType{Members: indexTypes}
+	}
+	return nil
+}
+
+// propertyNames restricting the names of additional properties to an enum (or any other string type) become a
+// mapped type that replaces the index signature of object, i.e. { id: number } & Partial<Record<'a' | 'b', T>>.
+// It's a Record without Partial if every name is required. Returns nil for other propertyNames, i.e. a pattern.
+func (g *generator) propertyNamesRecord(object *ObjectType, schema map[string]any, requiredProps []string, pointer string) (Type, error) {
+	propertyNamesPointer := pointer + jsonPointer("propertyNames")
+	propertyNames, ok := schema["propertyNames"].(map[string]any)
+	if !ok {
+		return nil, specErrorAt(propertyNamesPointer, "invalid propertyNames: %v", schema["propertyNames"])
+	}
+
+	// Property names are always strings, so the type is often left out, i.e. propertyNames: {enum: [a, b]}
+	_, hasType := propertyNames["type"]
+	_, hasRef := propertyNames["$ref"]
+	if !hasType && !hasRef && !isComposedSchema(propertyNames) && len(propertyNames) > 0 {
+		propertyNames = copySchema(propertyNames)
+		propertyNames["type"] = "string"
+	}
+
+	keyType, err := g.schemaToType(propertyNames, propertyNamesPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any string, i.e. propertyNames: {pattern: ...}
+	if _, ok := keyType.(*KeywordType); ok {
+		return nil, nil
+	}
+
+	valueType := object.IndexSignature
+	if valueType == nil {
+		if object.Closed {
+			// Only the properties are allowed anyway
+			return nil, nil
+		}
+		valueType = &KeywordType{Keyword: "any"}
+	}
+	object.IndexSignature = nil
+
+	var record Type = &ReferenceType{Name: "Record", TypeArguments: []Type{keyType, valueType}}
+	if !allRequired(propertyNames, requiredProps) {
+		record = &ReferenceType{Name: "Partial", TypeArguments: []Type{record}}
+	}
+	return record, nil
+}
+
+// Whether every value of the enum of a propertyNames schema is a required property
+func allRequired(propertyNames map[string]any, requiredProps []string) bool {
+	enum, ok := propertyNames["enum"].([]any)
+	if !ok || len(enum) == 0 {
+		return false
+	}
+
+	for _, value := range enum {
+		name, ok := value.(string)
+		if !ok || !itemInSlice(requiredProps, name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package typedfetch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDictionaryKeywords(t *testing.T) {
+	stringType := &KeywordType{Keyword: "string"}
+	numberType := &KeywordType{Keyword: "number"}
+	id := &Property{Name: "id", Optional: true, Type: numberType}
+	keys := &UnionType{Members: []Type{&LiteralType{Value: "a"}, &LiteralType{Value: "b"}}}
+
+	tests := []struct {
+		name    string
+		schemas string
+
+		// Type of the component A
+		want Type
+	}{
+		{
+			name:    "pattern properties",
+			schemas: "{A: {type: object, additionalProperties: false, patternProperties: {'^x-': {type: string}}}}",
+			want: &ObjectType{
+				PrefixSignatures: []*PrefixSignature{{Prefix: "x-", Type: stringType}},
+				Closed:           true,
+			},
+		},
+		{
+			name:    "prefix pattern with a wildcard",
+			schemas: "{A: {type: object, patternProperties: {'^x-.*$': {type: string}}}}",
+			want:    &ObjectType{PrefixSignatures: []*PrefixSignature{{Prefix: "x-", Type: stringType}}},
+		},
+		{
+			name:    "escaped prefix",
+			schemas: "{A: {type: object, patternProperties: {'^\\$x\\.': {type: string}}}}",
+			want:    &ObjectType{PrefixSignatures: []*PrefixSignature{{Prefix: "$x.", Type: stringType}}},
+		},
+		{
+			name:    "other pattern",
+			schemas: "{A: {type: object, patternProperties: {'^[a-z]+$': {type: integer}}}}",
+			want:    &ObjectType{IndexSignature: numberType},
+		},
+		{
+			name:    "prefix and other pattern",
+			schemas: "{A: {type: object, patternProperties: {'^x-': {type: string}, '^[a-z]+$': {type: integer}}}}",
+			want: &ObjectType{
+				PrefixSignatures: []*PrefixSignature{{Prefix: "x-", Type: stringType}},
+				IndexSignature:   &UnionType{Members: []Type{numberType, stringType}},
+			},
+		},
+		{
+			name:    "prefix and additional properties",
+			schemas: "{A: {type: object, additionalProperties: {type: boolean}, patternProperties: {'^x-': {type: string}}}}",
+			want: &ObjectType{
+				PrefixSignatures: []*PrefixSignature{{Prefix: "x-", Type: stringType}},
+				IndexSignature:   &UnionType{Members: []Type{&KeywordType{Keyword: "boolean"}, stringType}},
+			},
+		},
+		{
+			name:    "closed",
+			schemas: "{A: {type: object, additionalProperties: false, properties: {id: {type: integer}}}}",
+			want:    &ObjectType{Properties: []*Property{id}, Closed: true},
+		},
+		{
+			name:    "property names",
+			schemas: "{A: {type: object, properties: {id: {type: integer}}, additionalProperties: {type: string}, propertyNames: {enum: [a, b]}}}",
+			want: &IntersectionType{Members: []Type{
+				&ObjectType{Properties: []*Property{id}},
+				&ReferenceType{Name: "Partial", TypeArguments: []Type{
+					&ReferenceType{Name: "Record", TypeArguments: []Type{keys, stringType}},
+				}},
+			}},
+		},
+		{
+			name:    "required property names",
+			schemas: "{A: {type: object, required: [a, b], additionalProperties: {type: string}, propertyNames: {enum: [a, b]}}}",
+			want:    &ReferenceType{Name: "Record", TypeArguments: []Type{keys, stringType}},
+		},
+		{
+			name:    "property name pattern",
+			schemas: "{A: {type: object, additionalProperties: {type: string}, propertyNames: {pattern: '^[a-z]+$'}}}",
+			want:    &ObjectType{IndexSignature: stringType},
+		},
+		{
+			name:    "property names of a closed object",
+			schemas: "{A: {type: object, additionalProperties: false, properties: {id: {type: integer}}, propertyNames: {enum: [id]}}}",
+			want:    &ObjectType{Properties: []*Property{id}, Closed: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, test.schemas, "", Options{})
+
+			component := testComponent(t, model, "A")
+			if !reflect.DeepEqual(component.Type, test.want) {
+				t.Errorf("type = %s, want %s", typeJson(component.Type), typeJson(test.want))
+			}
+		})
+	}
+}
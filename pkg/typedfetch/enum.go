@@ -0,0 +1,168 @@
+package typedfetch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The type of an enum schema. With Options.EnumObjects every enum is an EnumType. Inline EnumTypes (named by
+// x-enum-varnames or EnumObjects) are hoisted to named types so that their const object keeps the member names.
+func (g *generator) enumType(schema map[string]any, enum []any, valueType string, pointer string) (Type, error) {
+	t, err := enumSchemaToType(schema, enum, valueType, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if union, ok := t.(*UnionType); ok && g.options.EnumObjects {
+		t = namedEnumType(union)
+	}
+
+	_, named := t.(*EnumType)
+	if (named || g.options.HoistInlineTypes) && g.isInline(pointer) {
+		return g.hoist(t, pointer), nil
+	}
+	return t, nil
+}
+
+// The values of a string or number enum as a union of literals, or an EnumType if they are named by x-enum-varnames
+func enumSchemaToType(schema map[string]any, enum []any, valueType string, pointer string) (Type, error) {
+	values := []any{}
+	for _, value := range enum {
+		// null is covered by the "null" member of a type list
+		if value == nil {
+			continue
+		}
+
+		switch value.(type) {
+		case string:
+			if valueType != "string" {
+				return nil, specErrorAt(pointer, "expected enum value to be a %s: %v", valueType, value)
+			}
+		case float64, int, int64, uint64:
+			if valueType != "number" {
+				return nil, specErrorAt(pointer, "expected enum value to be a %s: %v", valueType, value)
+			}
+		default:
+			return nil, specErrorAt(pointer, "expected enum value to be a %s: %v", valueType, value)
+		}
+
+		values = append(values, value)
+	}
+
+	names, err := getEnumStrings(schema, "x-enum-varnames", len(values), pointer)
+	if err != nil || names == nil {
+		union := &UnionType{}
+		for _, value := range values {
+			union.Members = append(union.Members, &LiteralType{Value: value})
+		}
+		return union, err
+	}
+
+	descriptions, err := getEnumStrings(schema, "x-enum-descriptions", len(values), pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	enumType := &EnumType{}
+	for i, value := range values {
+		member := &EnumMember{Name: names[i], Value: value}
+		if descriptions != nil {
+			member.Description = descriptions[i]
+		}
+		enumType.Members = append(enumType.Members, member)
+	}
+	return enumType, nil
+}
+
+// Name the members of a union of literals after their values, i.e. 'in-stock' -> InStock and -1 -> ValueMinus1
+func namedEnumType(union *UnionType) *EnumType {
+	enumType := &EnumType{}
+	used := map[string]bool{}
+	for _, member := range union.Members {
+		value := member.(*LiteralType).Value
+
+		var name string
+		switch value := value.(type) {
+		case string:
+			name = toIdentifier(value, CasingPascal)
+			if value == "" {
+				name = "Empty"
+			}
+		case float64:
+			name = "Value" + enumNumberReplacer.Replace(strconv.FormatFloat(value, 'f', -1, 64))
+		default:
+			name = "Value" + enumNumberReplacer.Replace(fmt.Sprint(value))
+		}
+
+		enumType.Members = append(enumType.Members, &EnumMember{Name: uniqueName(name, used), Value: value})
+	}
+	return enumType
+}
+
+var enumNumberReplacer = strings.NewReplacer("-", "Minus", ".", "_")
+
+// The const object of a named enum and the union type of its values:
+//
+//	const Name = { A: 'a', B: 'b' } as const;
+//	type Name = (typeof Name)[keyof typeof Name];
+//
+// Declarations can't have values, so a .d.ts declares the const object instead.
+func (p *tsPrinter) printEnumObject(doc, name string, enum *EnumType) []string {
+	lines := []string{}
+	if doc != "" {
+		lines = append(lines, doc)
+	}
+
+	if p.emitTs {
+		lines = append(lines, fmt.Sprintf("%sconst %s = {", p.export(), name))
+	} else {
+		lines = append(lines, fmt.Sprintf("%sdeclare const %s: {", p.export(), name))
+	}
+
+	for _, member := range enum.Members {
+		docString := buildDocString(member.Description, "")
+		if docString != "" {
+			lines = append(lines, p.indent(1)+docString)
+		}
+
+		value := p.print(toTsType(&LiteralType{Value: member.Value}), 1, 0, false)
+		if p.emitTs {
+			lines = append(lines, fmt.Sprintf("%s%s: %s,", p.indent(1), p.propertyName(member.Name), value))
+		} else {
+			lines = append(lines, fmt.Sprintf("%sreadonly %s: %s;", p.indent(1), p.propertyName(member.Name), value))
+		}
+	}
+
+	if p.emitTs {
+		lines = append(lines, "} as const;")
+	} else {
+		lines = append(lines, "};")
+	}
+
+	return append(lines, fmt.Sprintf("%stype %s = (typeof %s)[keyof typeof %s];", p.export(), name, name, name))
+}
+
+// A list of strings, one for each enum value, from a vendor extension; nil if there is none
+func getEnumStrings(schema map[string]any, extension string, count int, pointer string) ([]string, error) {
+	list, ok := schema[extension]
+	if !ok {
+		return nil, nil
+	}
+
+	extensionPointer := pointer + jsonPointer(extension)
+	items, ok := list.([]any)
+	if !ok || len(items) != count {
+		return nil, specErrorAt(extensionPointer, "expected a list of %d strings, one for each enum value: %v", count, list)
+	}
+
+	values := []string{}
+	for i, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, specErrorAt(extensionPointer+jsonPointer(fmt.Sprint(i)), "expected a string: %v", item)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,121 @@
+package typedfetch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
+)
+
+func TestVendorExtensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		options Options
+
+		// Type of the component A
+		want Type
+	}{
+		{
+			name:    "enum varnames",
+			schemas: "{A: {type: string, enum: [dog, cat], x-enum-varnames: [Dog, Cat], x-enum-descriptions: [A dog, '']}}",
+			want: &EnumType{Members: []*EnumMember{
+				{Name: "Dog", Value: "dog", Description: "A dog"},
+				{Name: "Cat", Value: "cat"},
+			}},
+		},
+		{
+			name:    "number enum varnames",
+			schemas: "{A: {type: integer, enum: [1, 2, null], x-enum-varnames: [One, Two]}}",
+			want: &EnumType{Members: []*EnumMember{
+				{Name: "One", Value: float64(1)},
+				{Name: "Two", Value: float64(2)},
+			}},
+		},
+		{
+			name:    "inline enum varnames",
+			schemas: "{A: {type: object, properties: {kind: {type: string, enum: [dog], x-enum-varnames: [Dog]}}}}",
+			want: &ObjectType{Properties: []*Property{
+				{Name: "kind", Optional: true, Type: &ReferenceType{Name: "ComponentSchemaAKind"}},
+			}},
+		},
+		{
+			name:    "enum objects",
+			schemas: "{A: {type: string, enum: [in-stock, '', a b]}}",
+			options: Options{EnumObjects: true, EmitTs: true},
+			want: &EnumType{Members: []*EnumMember{
+				{Name: "InStock", Value: "in-stock"},
+				{Name: "Empty", Value: ""},
+				{Name: "AB", Value: "a b"},
+			}},
+		},
+		{
+			name:    "typescript type",
+			schemas: "{A: {type: string, format: date-time, x-typescript-type: Date}}",
+			want:    &RawType{TypeScript: "Date"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, test.schemas, "", test.options)
+
+			component := testComponent(t, model, "A")
+			if !reflect.DeepEqual(component.Type, test.want) {
+				t.Errorf("type = %s, want %s", typeJson(component.Type), typeJson(test.want))
+			}
+		})
+	}
+}
+
+func TestVendorExtensionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{
+			name:    "too few varnames",
+			schema:  "{type: string, enum: [dog, cat], x-enum-varnames: [Dog]}",
+			wantErr: "expected a list of 2 strings",
+		},
+		{
+			name:    "varname that isn't a string",
+			schema:  "{type: string, enum: [dog], x-enum-varnames: [1]}",
+			wantErr: "expected a string",
+		},
+		{
+			name:    "descriptions without varnames are ignored",
+			schema:  "{type: string, enum: [dog], x-enum-descriptions: [1, 2]}",
+			wantErr: "",
+		},
+		{
+			name:    "empty typescript type",
+			schema:  "{type: string, x-typescript-type: ''}",
+			wantErr: "expected a TypeScript type",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := "openapi: 3.1.0\ninfo: {title: test, version: '1'}\npaths: {}\ncomponents: {schemas: {A: " + test.schema + "}}\n"
+			reflector, err := loader.LoadBytes([]byte(spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = GenerateTypedFetchWithOptions(reflector, Options{})
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
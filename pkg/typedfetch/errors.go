@@ -0,0 +1,98 @@
+package typedfetch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SpecError is an invalid or unsupported construct in the OpenAPI document
+type SpecError struct {
+	// JSON Pointer (RFC 6901) to the offending node, i.e. /components/schemas/Pet/properties/tags
+	Pointer string
+	Message string
+}
+
+func (e *SpecError) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+// SpecErrors is every SpecError found in the document, in document order
+type SpecErrors []*SpecError
+
+func (e SpecErrors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Flatten err (a SpecError, SpecErrors, or any other error) onto the list
+func (e SpecErrors) append(err error) SpecErrors {
+	var specErrs SpecErrors
+	if errors.As(err, &specErrs) {
+		return append(e, specErrs...)
+	}
+
+	var specErr *SpecError
+	if errors.As(err, &specErr) {
+		return append(e, specErr)
+	}
+
+	return append(e, &SpecError{Message: err.Error()})
+}
+
+// Return nil for an empty list, so that the result can be returned as an error
+func (e SpecErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func specErrorf(format string, args ...any) error {
+	return &SpecError{Message: fmt.Sprintf(format, args...)}
+}
+
+func specErrorAt(pointer string, format string, args ...any) error {
+	return &SpecError{Pointer: pointer, Message: fmt.Sprintf(format, args...)}
+}
+
+// Prefix the pointer of err with pointer, i.e. /items + /properties/id -> /items/properties/id
+// Any other error becomes a SpecError located at pointer
+func wrapSpecError(err error, pointer string) error {
+	var specErrs SpecErrors
+	if errors.As(err, &specErrs) {
+		wrapped := SpecErrors{}
+		for _, specErr := range specErrs {
+			wrapped = append(wrapped, &SpecError{Pointer: pointer + specErr.Pointer, Message: specErr.Message})
+		}
+		return wrapped
+	}
+
+	var specErr *SpecError
+	if errors.As(err, &specErr) {
+		return &SpecError{Pointer: pointer + specErr.Pointer, Message: specErr.Message}
+	}
+	return &SpecError{Pointer: pointer, Message: err.Error()}
+}
+
+// Build a JSON Pointer from unescaped tokens, i.e. ("paths", "/pet/{petId}", "get") -> /paths/~1pet~1{petId}/get
+func jsonPointer(tokens ...string) string {
+	pointer := ""
+	for _, token := range tokens {
+		token = strings.ReplaceAll(token, "~", "~0")
+		token = strings.ReplaceAll(token, "/", "~1")
+		pointer += "/" + token
+	}
+	return pointer
+}
+
+// Convert a local reference to a JSON Pointer, i.e. #/components/schemas/Pet -> /components/schemas/Pet
+func refToPointer(ref string) string {
+	return strings.TrimPrefix(ref, "#")
+}
@@ -0,0 +1,124 @@
+package typedfetch
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Selects operations by tag, path, method and operationId. An empty list matches any operation.
+type OperationFilter struct {
+	Tags []string `yaml:"tags"`
+
+	// Globs matched against the path template: * matches within a segment and ** across segments, i.e. /store/** or /pet/*/uploadImage
+	Paths []string `yaml:"paths"`
+
+	// Case insensitive, i.e. get
+	Methods []string `yaml:"methods"`
+
+	OperationIds []string `yaml:"operationIds"`
+}
+
+func (f OperationFilter) isEmpty() bool {
+	return len(f.Tags) == 0 && len(f.Paths) == 0 && len(f.Methods) == 0 && len(f.OperationIds) == 0
+}
+
+// Whether the operation is generated: it has to match every list of options.Include,
+// and is dropped if it matches any list of options.Exclude
+func (g *generator) isOperationSelected(op *openapi31.Operation, method, path string) bool {
+	include := g.options.Include
+	if len(include.Tags) > 0 && !matchesTag(include.Tags, op) ||
+		len(include.Paths) > 0 && !matchesPath(include.Paths, path) ||
+		len(include.Methods) > 0 && !matchesMethod(include.Methods, method) ||
+		len(include.OperationIds) > 0 && !matchesOperationId(include.OperationIds, op) {
+		return false
+	}
+
+	exclude := g.options.Exclude
+	return !matchesTag(exclude.Tags, op) &&
+		!matchesPath(exclude.Paths, path) &&
+		!matchesMethod(exclude.Methods, method) &&
+		!matchesOperationId(exclude.OperationIds, op)
+}
+
+type selectedOperation struct {
+	OperationMethodTuple
+	Path string
+}
+
+// Every operation to generate, sorted by path
+func (g *generator) selectedOperations() []selectedOperation {
+	operations := []selectedOperation{}
+
+	sortedPaths := sortedMapKeys(specPaths(g.reflector))
+	for _, path := range sortedPaths {
+		item := specPaths(g.reflector)[path]
+		for _, method := range getPathItemMethods(&item) {
+			if method.Operation == nil || !g.isOperationSelected(method.Operation, method.Method, path) {
+				continue
+			}
+
+			operations = append(operations, selectedOperation{OperationMethodTuple: method, Path: path})
+		}
+	}
+
+	return operations
+}
+
+func (g *generator) isFiltering() bool {
+	return !g.options.Include.isEmpty() || !g.options.Exclude.isEmpty()
+}
+
+func matchesTag(tags []string, op *openapi31.Operation) bool {
+	for _, tag := range op.Tags {
+		if slices.Contains(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPath(globs []string, path string) bool {
+	for _, glob := range globs {
+		if globToRegexp(glob).MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesOperationId(operationIds []string, op *openapi31.Operation) bool {
+	return op.ID != nil && slices.Contains(operationIds, *op.ID)
+}
+
+// i.e. /store/** -> ^/store/.*$
+func globToRegexp(glob string) *regexp.Regexp {
+	pattern := strings.Builder{}
+	pattern.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			pattern.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			pattern.WriteString("[^/]*")
+		case glob[i] == '?':
+			pattern.WriteString("[^/]")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
@@ -0,0 +1,141 @@
+package typedfetch
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{glob: "/store/*", path: "/store/inventory", want: true},
+		{glob: "/store/*", path: "/store/order/{orderId}", want: false},
+		{glob: "/store/**", path: "/store/order/{orderId}", want: true},
+		{glob: "/store/**", path: "/store", want: false},
+		{glob: "/pet/*/uploadImage", path: "/pet/{petId}/uploadImage", want: true},
+		{glob: "/pet/*/uploadImage", path: "/pet/a/b/uploadImage", want: false},
+		{glob: "/pet/**/uploadImage", path: "/pet/a/b/uploadImage", want: true},
+		{glob: "/pet/?", path: "/pet/a", want: true},
+		{glob: "/pet/?", path: "/pet/ab", want: false},
+		{glob: "/store", path: "/store/inventory", want: false},
+		{glob: "/v1.0/items", path: "/v1.0/items", want: true},
+		{glob: "/v1.0/items", path: "/v1x0/items", want: false},
+		{glob: "/pet/{petId}", path: "/pet/{petId}", want: true},
+		{glob: "/search+(all)", path: "/search+(all)", want: true},
+		{glob: "/search+(all)", path: "/searchhall", want: false},
+		{glob: "/a|b", path: "/a", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.glob+" "+test.path, func(t *testing.T) {
+			got := globToRegexp(test.glob).MatchString(test.path)
+			if got != test.want {
+				t.Errorf("match = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsOperationSelected(t *testing.T) {
+	const ok = "responses: {'200': {description: ok}}"
+	paths := "{" +
+		"'/pet/{petId}': {get: {operationId: getPetById, tags: [pet], " + ok + "}, delete: {operationId: deletePet, tags: [pet], " + ok + "}}, " +
+		"'/pet/{petId}/uploadImage': {post: {operationId: uploadFile, tags: [pet, files], " + ok + "}}, " +
+		"/store/inventory: {get: {operationId: getInventory, tags: [store], " + ok + "}}, " +
+		"'/store/order/{orderId}': {get: {tags: [store], " + ok + "}}, " +
+		"/v1.0/items: {get: {" + ok + "}}}"
+
+	tests := []struct {
+		name    string
+		include OperationFilter
+		exclude OperationFilter
+		want    []string
+	}{
+		{
+			name: "everything",
+			want: []string{
+				"DELETE /pet/{petId}", "GET /pet/{petId}", "POST /pet/{petId}/uploadImage",
+				"GET /store/inventory", "GET /store/order/{orderId}", "GET /v1.0/items",
+			},
+		},
+		{
+			name:    "tag",
+			include: OperationFilter{Tags: []string{"files", "store"}},
+			want:    []string{"POST /pet/{petId}/uploadImage", "GET /store/inventory", "GET /store/order/{orderId}"},
+		},
+		{
+			name:    "method",
+			include: OperationFilter{Methods: []string{"delete", "Post"}},
+			want:    []string{"DELETE /pet/{petId}", "POST /pet/{petId}/uploadImage"},
+		},
+		{
+			name:    "operationId",
+			include: OperationFilter{OperationIds: []string{"getInventory", "getpetbyid"}},
+			want:    []string{"GET /store/inventory"},
+		},
+		{
+			name:    "single segment path",
+			include: OperationFilter{Paths: []string{"/pet/*", "/store/*"}},
+			want:    []string{"DELETE /pet/{petId}", "GET /pet/{petId}", "GET /store/inventory"},
+		},
+		{
+			name:    "any segment path",
+			include: OperationFilter{Paths: []string{"/store/**"}},
+			want:    []string{"GET /store/inventory", "GET /store/order/{orderId}"},
+		},
+		{
+			name:    "escaped path",
+			include: OperationFilter{Paths: []string{"/v1.0/*"}},
+			want:    []string{"GET /v1.0/items"},
+		},
+		{
+			name:    "every include list has to match",
+			include: OperationFilter{Tags: []string{"pet"}, Methods: []string{"get", "post"}},
+			want:    []string{"GET /pet/{petId}", "POST /pet/{petId}/uploadImage"},
+		},
+		{
+			name:    "exclude",
+			exclude: OperationFilter{Tags: []string{"pet"}},
+			want:    []string{"GET /store/inventory", "GET /store/order/{orderId}", "GET /v1.0/items"},
+		},
+		{
+			name:    "any exclude list drops",
+			exclude: OperationFilter{Methods: []string{"delete"}, Paths: []string{"/store/**"}, OperationIds: []string{"uploadFile"}},
+			want:    []string{"GET /pet/{petId}", "GET /v1.0/items"},
+		},
+		{
+			name:    "exclude after include",
+			include: OperationFilter{Tags: []string{"pet"}},
+			exclude: OperationFilter{Methods: []string{"DELETE"}, Tags: []string{"files"}},
+			want:    []string{"GET /pet/{petId}"},
+		},
+		{
+			name:    "exclude wins over include",
+			include: OperationFilter{OperationIds: []string{"deletePet"}},
+			exclude: OperationFilter{OperationIds: []string{"deletePet"}},
+			want:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, "{}", paths, Options{Include: test.include, Exclude: test.exclude})
+
+			got := []string{}
+			for _, operation := range model.Operations {
+				got = append(got, operation.Method+" "+operation.Path)
+			}
+
+			slices.Sort(got)
+			want := slices.Clone(test.want)
+			slices.Sort(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("operations = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package typedfetch
+
+import (
+	"fmt"
+)
+
+type QuoteStyle string
+
+const (
+	QuoteSingle QuoteStyle = "single" // default
+	QuoteDouble QuoteStyle = "double"
+)
+
+// Layout of the generated TypeScript. Empty fields use the defaults.
+type Format struct {
+	// Spaces per indentation level, default 4
+	Indent  int   `yaml:"indent"`
+	UseTabs *bool `yaml:"useTabs"`
+
+	// Unions that don't fit within this many columns are broken over several lines, default 120; -1 to never break
+	LineWidth int `yaml:"lineWidth"`
+
+	// Quotes of string literals, quoted property names and import paths
+	Quote QuoteStyle `yaml:"quote"`
+}
+
+func (f Format) withDefaults() Format {
+	if f.Indent == 0 {
+		f.Indent = 4
+	}
+	if f.LineWidth == 0 {
+		f.LineWidth = 120
+	}
+	if f.Quote == "" {
+		f.Quote = QuoteSingle
+	}
+	return f
+}
+
+func (f Format) Validate() error {
+	f = f.withDefaults()
+
+	if f.Indent < 0 {
+		return fmt.Errorf("format: indent must be positive: %d", f.Indent)
+	}
+
+	if f.LineWidth < -1 {
+		return fmt.Errorf("format: lineWidth must be positive or -1: %d", f.LineWidth)
+	}
+
+	switch f.Quote {
+	case QuoteSingle, QuoteDouble:
+	default:
+		return fmt.Errorf("format: unknown quote %q (expected single or double)", f.Quote)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package typedfetch
+
+import (
+	"fmt"
+)
+
+type Int64Type string
+
+const (
+	Int64AsNumber Int64Type = "number" // default
+	Int64AsBigInt Int64Type = "bigint"
+	Int64AsString Int64Type = "string"
+)
+
+type DatesType string
+
+const (
+	DatesAsString DatesType = "string" // default
+	DatesAsBrand  DatesType = "brand"  // DateString and DateTimeString
+	DatesAsDate   DatesType = "date"   // Date, which needs the response to be revived, JSON.parse returns strings
+)
+
+// Opt-in types for schema formats. Empty fields keep the plain number and string types.
+type FormatTypes struct {
+	// Numbers with format: int64
+	Int64 Int64Type `yaml:"int64"`
+
+	// Strings with format: date or date-time
+	Dates DatesType `yaml:"dates"`
+
+	// Branded strings for format: byte (Base64String), uuid (Uuid), email (Email) and uri (Uri), i.e.
+	// a Uuid is assignable to string but a string isn't assignable to Uuid
+	Brands *bool `yaml:"brands"`
+}
+
+// A string that is only assignable from the same brand, i.e. type Uuid = Branded<string, 'Uuid'>
+type Brand struct {
+	Name string
+	Base Type
+}
+
+var brandedFormats = map[string]string{
+	"byte":  "Base64String",
+	"uuid":  "Uuid",
+	"email": "Email",
+	"uri":   "Uri",
+}
+
+var brandedDateFormats = map[string]string{
+	"date":      "DateString",
+	"date-time": "DateTimeString",
+}
+
+func (f FormatTypes) Validate() error {
+	switch f.Int64 {
+	case "", Int64AsNumber, Int64AsBigInt, Int64AsString:
+	default:
+		return fmt.Errorf("formatTypes: unknown int64 type %q (expected number, bigint or string)", f.Int64)
+	}
+
+	switch f.Dates {
+	case "", DatesAsString, DatesAsBrand, DatesAsDate:
+	default:
+		return fmt.Errorf("formatTypes: unknown dates type %q (expected string, brand or date)", f.Dates)
+	}
+
+	return nil
+}
+
+// The type of a number or integer, by its format
+func (g *generator) numberFormatType(format string) Type {
+	if format == "int64" {
+		switch g.options.FormatTypes.Int64 {
+		case Int64AsBigInt:
+			return &KeywordType{Keyword: "bigint"}
+		case Int64AsString:
+			return &KeywordType{Keyword: "string"}
+		}
+	}
+
+	return &KeywordType{Keyword: "number"}
+}
+
+// The type of a string (without enum) by its format, or nil for a plain string
+func (g *generator) stringFormatType(format string) Type {
+	formatTypes := g.options.FormatTypes
+	if name, ok := brandedDateFormats[format]; ok {
+		switch formatTypes.Dates {
+		case DatesAsDate:
+			return &ReferenceType{Name: "Date"}
+		case DatesAsBrand:
+			return g.brand(name)
+		}
+	}
+
+	if name, ok := brandedFormats[format]; ok && formatTypes.Brands != nil && *formatTypes.Brands {
+		return g.brand(name)
+	}
+
+	return nil
+}
+
+// Reference a branded string, declaring it in the model
+func (g *generator) brand(name string) Type {
+	g.brands[name] = true
+	return &ReferenceType{Name: name}
+}
+
+func (g *generator) modelBrands() []Brand {
+	brands := []Brand{}
+	for _, name := range sortedMapKeys(g.brands) {
+		brands = append(brands, Brand{Name: name, Base: &KeywordType{Keyword: "string"}})
+	}
+	return brands
+}
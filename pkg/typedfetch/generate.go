@@ -6,67 +6,298 @@ import (
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
+type Options struct {
+	// Keep going after an invalid or unsupported construct and return every error found (as SpecErrors)
+	// together with the output generated for everything that succeeded
+	CollectErrors bool
+
+	// Translate any schema that can't be translated to unknown (with a warning) instead of failing,
+	// and objects without properties to Record<string, unknown>
+	Lenient bool
+
+	// Called for every warning, i.e. each schema degraded to unknown in lenient mode
+	OnWarning func(warning *SpecError)
+
+	// Only generate the operations matching Include and not matching Exclude (see OperationFilter).
+	// Component schemas that none of the remaining operations reference are left out.
+	Include OperationFilter
+	Exclude OperationFilter
+
+	// Export every generated type, not just Client, so they can be imported
+	ExportTypes bool
+
+	// Generate a .ts module rather than ambient .d.ts declarations. Implies ExportTypes;
+	// GenerateTypedFetchFiles names the modules .ts.
+	EmitTs bool
+
+	// Also generate aliases grouped by namespace, i.e. Components.Schemas.Pet and Operations.GetPetById.Body
+	Namespaces bool
+
+	// Called for every schema, to override the generated type (see Hook). Hooks are tried in order, before Mappings.
+	Hooks []Hook
+
+	// Override the type of schemas by format or vendor extension, and import it, see Mapping. Tried in order,
+	// before the built-in translation.
+	Mappings []Mapping
+
+	// Opt-in types for formats like int64, date-time and uuid, see FormatTypes
+	FormatTypes FormatTypes
+
+	// Names of the generated types, see Naming
+	Naming Naming
+
+	// Indentation, line width and quotes of the generated TypeScript, see Format
+	Format Format
+
+	// Also generate a const object of the values of each enum, i.e. ComponentSchemaPetStatus.Available, hoisting
+	// inline enums to named types after their location, i.e. ComponentSchemaPetStatus for the status property of Pet.
+	// Requires EmitTs, since a .d.ts can't have runtime values.
+	EnumObjects bool
+
+	// Hoist every inline object and enum schema to a named type after its location, i.e. BodyPostPetCategory for the
+	// category property of the body of POST /pet, so that it can be referenced
+	HoistInlineTypes bool
+
+	// Leave out component schemas that no operation references, directly or transitively, even when not filtering
+	TreeShake bool
+}
+
+type generator struct {
+	reflector *openapi31.Reflector
+	options   Options
+	naming    Naming
+
+	// Type names by component and operation names by endpoint, computed on first use
+	componentNames map[string]string
+	operationNames map[string]string
+
+	// Errors collected so far when options.CollectErrors is set
+	errors SpecErrors
+
+	// Options.Hooks followed by Options.Mappings
+	hooks []Hook
+
+	// Imports of the overridden types generated so far
+	imports map[Import]bool
+
+	// Names of the branded strings referenced so far
+	brands map[string]bool
+
+	// The named type being built, see hoistOwner
+	owner hoistOwner
+
+	// Names of the inline schemas hoisted to named types so far by pointer, and their types in the order they were hoisted
+	hoistedNames map[string]string
+	inlineTypes  []*NamedType
+
+	// Every type name declared, computed on first use
+	typeNames map[string]bool
+
+	// Set by buildModel
+	model *Model
+
+	// File declaring each type name, set by generateFiles
+	declaredIn map[string]string
+
+	printer *tsPrinter
+}
+
 func GenerateTypedFetch(reflector *openapi31.Reflector) (string, error) {
-	lines := []string{
-		"// Code generated by typed-fetch. DO NOT EDIT.",
-		"// https://github.com/RPGillespie6/typed-fetch",
-		"",
-	}
+	return GenerateTypedFetchWithOptions(reflector, Options{})
+}
 
-	// Generate shared types
-	sharedTypesLines, err := generateSharedTypes()
+// If options.CollectErrors is set, the output is returned even if there are errors
+func GenerateTypedFetchWithOptions(reflector *openapi31.Reflector, options Options) (string, error) {
+	gen, err := NewGenerator(reflector, options)
 	if err != nil {
 		return "", err
 	}
-	lines = append(lines, sharedTypesLines...)
+	return gen.Generate()
+}
 
-	// Generate all component types
-	componentTypesLines, err := generateComponentSchemaTypes(reflector)
+func (g *generator) generate() (string, error) {
+	options := g.options
+	lines := generatedHeader()
+
+	model, err := g.buildModel()
 	if err != nil {
 		return "", err
 	}
-	lines = append(lines, componentTypesLines...)
 
-	// Generate all requests/response/url types
-	requestTypesLines, err := generateOperationTypes(reflector)
+	p := g.printer
+	lines = append(lines, p.printImports(model.Imports)...)
+	lines = append(lines, p.printSharedTypes(model.Brands)...)
+	lines = append(lines, p.printComponentTypes(model.Components)...)
+	lines = append(lines, p.printInlineTypes(model.InlineTypes)...)
+	lines = append(lines, p.printOperationTypes(model.Operations)...)
+	lines = append(lines, p.printClient(model.Operations)...)
+
+	if options.Namespaces {
+		declared := map[string]bool{}
+		for _, name := range modelTypeNames(model) {
+			declared[name] = true
+		}
+		lines = append(append(lines, ""), p.printNamespaces(model, declared)...)
+	}
+
+	return strings.Join(lines, "\n"), g.errors.orNil()
+}
+
+// Build the model of the components and selected operations. When collecting errors, the parts that failed are
+// marked as such and the errors are left in g.errors; otherwise the first error is returned.
+func (g *generator) buildModel() (*Model, error) {
+	components, err := g.buildComponents()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	lines = append(lines, requestTypesLines...)
 
-	// Generate the client interface
-	clientLines, err := generateClient(reflector)
+	operations, err := g.buildOperations(g.selectedOperations())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	lines = append(lines, clientLines...)
 
-	return strings.Join(lines, "\n"), nil
+	g.model = &Model{
+		Components:  components,
+		InlineTypes: g.inlineTypes,
+		Operations:  operations,
+		Imports:     sortedImports(g.imports),
+		Brands:      g.modelBrands(),
+	}
+	return g.model, nil
 }
 
-func generateSharedTypes() ([]string, error) {
-	lines := []string{
-		"// Shared types",
+func newGenerator(reflector *openapi31.Reflector, options Options) *generator {
+	printer := newTsPrinter(options.Format, options.EmitTs)
+	printer.exportTypes = options.ExportTypes || options.EmitTs
+
+	return &generator{
+		reflector: reflector,
+		options:   options,
+		naming:    options.Naming.withDefaults(),
+		printer:   printer,
+		hooks:     append(append([]Hook{}, options.Hooks...), mappingHook(options.Mappings)),
+		imports:   map[Import]bool{},
+		brands:    map[string]bool{},
+
+		hoistedNames: map[string]string{},
+	}
+}
+
+// The components of the spec, empty if it has none
+func specComponents(reflector *openapi31.Reflector) *openapi31.Components {
+	if reflector.Spec.Components == nil {
+		return &openapi31.Components{}
+	}
+	return reflector.Spec.Components
+}
+
+// The path items of the spec by path, empty if it has no paths
+func specPaths(reflector *openapi31.Reflector) map[string]openapi31.PathItem {
+	if reflector.Spec.Paths == nil {
+		return nil
+	}
+	return reflector.Spec.Paths.MapOfPathItemValues
+}
+
+// First line of every generated file
+const GeneratedComment = "// Code generated by typed-fetch. DO NOT EDIT."
+
+func generatedHeader() []string {
+	return []string{
+		GeneratedComment,
+		"// https://github.com/RPGillespie6/typed-fetch",
 		"",
-		strings.TrimSpace(`
-type RequestInitExtended = {
-    // Local headers -- same as RequestInit but with a Record<string, string> instead of HeadersInit
-    headers?: Record<string, string>;
+	}
+}
+
+func (g *generator) warn(warning *SpecError) {
+	if g.options.OnWarning != nil {
+		g.options.OnWarning(warning)
+	}
+}
 
-    // If you want the response data to be parse as something other than json (json is default)
-    parseAs?: "json" | "text" | "blob" | "arrayBuffer" | "formData" | "bytes";
+// When collecting errors, record err and return true so the caller can skip the failed item and keep going.
+// Otherwise return false, and the caller should return err.
+func (g *generator) collect(err error) bool {
+	if !g.options.CollectErrors {
+		return false
+	}
 
-    // local body serializer -- allows you to customize how the body is serialized before sending
-    // normally not needed unless you are using something like XML instead of JSON
-    bodySerializer?: (body: any) => BodyInit | null;
+	g.errors = g.errors.append(err)
+	return true
+}
 
-    // local query serializer -- allows you to customize how the query is serialized before sending
-    // normally not needed unless you are using some custom array serialization like {foo: [1,2,3,4]} => ?foo=1;2;3;4
-    querySerializer?: (query: any) => string;
-}	
-`),
+// Names of the types every part of the output declares, except the namespaces' aliases
+func modelTypeNames(model *Model) []string {
+	names := sharedTypeNames(model.Brands)
+	names = append(names, namedTypeNames(model.Components)...)
+	names = append(names, namedTypeNames(model.InlineTypes)...)
+	names = append(names, operationTypeNames(model.Operations)...)
+	return append(names, clientTypeNames(model.Operations)...)
+}
+
+// Names of the types printSharedTypes declares
+func sharedTypeNames(brands []Brand) []string {
+	names := []string{"RequestInitExtended"}
+	if len(brands) > 0 {
+		names = append(names, "Branded")
+	}
+	for _, b := range brands {
+		names = append(names, b.Name)
 	}
+	return names
+}
+
+func (p *tsPrinter) printSharedTypes(brands []Brand) []string {
+	requestInitExtended := &tsObject{members: []*tsMember{
+		{
+			comments: []string{"Local headers -- same as RequestInit but with a Record<string, string> instead of HeadersInit"},
+			name:     "headers",
+			optional: true,
+			typ:      tsReferenceTo("Record", &tsKeyword{"string"}, &tsKeyword{"string"}),
+		},
+		{
+			comments: []string{"If you want the response data to be parse as something other than json (json is default)"},
+			name:     "parseAs",
+			optional: true,
+			typ:      tsStringLiterals("json", "text", "blob", "arrayBuffer", "formData", "bytes"),
+		},
+		{
+			comments: []string{
+				"local body serializer -- allows you to customize how the body is serialized before sending",
+				"normally not needed unless you are using something like XML instead of JSON",
+			},
+			name:     "bodySerializer",
+			optional: true,
+			typ:      &tsRaw{"(body: any) => BodyInit | null"},
+		},
+		{
+			comments: []string{
+				"local query serializer -- allows you to customize how the query is serialized before sending",
+				"normally not needed unless you are using some custom array serialization like {foo: [1,2,3,4]} => ?foo=1;2;3;4",
+			},
+			name:     "querySerializer",
+			optional: true,
+			typ:      &tsRaw{"(query: any) => string"},
+		},
+	}}
+
+	lines := []string{
+		"// Shared types",
+		"",
+	}
+	lines = append(lines, p.typeAlias("", "RequestInitExtended", requestInitExtended)...)
 	lines = append(lines, "")
 
-	return lines, nil
+	if len(brands) > 0 {
+		brand := &tsObject{inline: true, members: []*tsMember{{readonly: true, name: "__brand", typ: tsReferenceTo("B")}}}
+		lines = append(lines, "// Branded types, i.e. a Uuid is assignable to string, but a string isn't assignable to Uuid")
+		lines = append(lines, p.typeAlias("", "Branded<T, B extends string>", &tsIntersection{[]tsType{tsReferenceTo("T"), brand}})...)
+		for _, b := range brands {
+			lines = append(lines, p.typeAlias("", b.Name, tsReferenceTo("Branded", toTsType(b.Base), &tsStringLiteral{b.Name}))...)
+		}
+		lines = append(lines, "")
+	}
+
+	return lines
 }
@@ -0,0 +1,124 @@
+package typedfetch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
+)
+
+// A spec without components or paths is valid, and references into the missing components are spec errors
+func TestGenerateWithoutComponentsOrPaths(t *testing.T) {
+	const header = "openapi: 3.1.0\ninfo: {title: test, version: '1'}\n"
+	const response = "responses: {'200': {description: ok}}"
+
+	tests := []struct {
+		name    string
+		spec    string
+		options Options
+
+		// Error message, if the spec is invalid
+		wantErr string
+	}{
+		{name: "neither", spec: header},
+		{name: "neither with every option", spec: header, options: Options{
+			ExportTypes: true, EmitTs: true, Namespaces: true, EnumObjects: true, HoistInlineTypes: true, TreeShake: true,
+		}},
+		{name: "no components", spec: header + "paths: {/pets: {get: {" + response + "}}}"},
+		{name: "no paths", spec: header + "components: {schemas: {Pet: {type: string}}}"},
+		{
+			name:    "parameter reference",
+			spec:    header + "paths: {/pets: {get: {parameters: [{$ref: '#/components/parameters/Limit'}], " + response + "}}}",
+			wantErr: "parameter Limit not found",
+		},
+		{
+			name:    "request body reference",
+			spec:    header + "paths: {/pets: {post: {requestBody: {$ref: '#/components/requestBodies/Pet'}, " + response + "}}}",
+			wantErr: "requestBody Pet not found",
+		},
+		{
+			name:    "response reference",
+			spec:    header + "paths: {/pets: {get: {responses: {'200': {$ref: '#/components/responses/Pets'}}}}}",
+			wantErr: "response Pets not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reflector, err := loader.LoadBytes([]byte(test.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, split := range []bool{false, true} {
+				if split {
+					_, err = GenerateTypedFetchFiles(reflector, test.options)
+				} else {
+					_, err = GenerateTypedFetchWithOptions(reflector, test.options)
+				}
+
+				if test.wantErr == "" {
+					if err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", test.wantErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateExportTypes(t *testing.T) {
+	spec := "openapi: 3.1.0\ninfo: {title: test, version: '1'}\ncomponents:\n  schemas:\n" +
+		"    A: {type: string, description: \"Kinds:\\ntype A\\nconst B\"}\n" +
+		"    B: {type: string, enum: [a], x-enum-varnames: [A]}\n"
+
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{
+			name: "declarations",
+			want: []string{"\ntype ComponentSchemaA = string;", "\ndeclare const ComponentSchemaB: {", "\ntype DataResponse<D>", "\nexport interface Client"},
+		},
+		{
+			name:    "exported",
+			options: Options{ExportTypes: true},
+			want:    []string{"\nexport type ComponentSchemaA = string;", "\nexport declare const ComponentSchemaB: {", "\nexport type DataResponse<D>"},
+		},
+		{
+			name:    "ts",
+			options: Options{EmitTs: true},
+			want:    []string{"\nexport type ComponentSchemaA = string;", "\nexport const ComponentSchemaB = {", "\nexport type OptionalKeys<T"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reflector, err := loader.LoadBytes([]byte(spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := GenerateTypedFetchWithOptions(reflector, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Doc comment lines are left alone
+			if !strings.Contains(output, "\ntype A\nconst B */") {
+				t.Errorf("doc comment changed:\n%s", output)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected %q in:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package typedfetch
+
+import (
+	"fmt"
+
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Generator generates the typed-fetch TypeScript for a document, for embedding typed-fetch in Go tooling:
+//
+//	gen, err := typedfetch.NewGenerator(document.Reflector, typedfetch.Options{Lenient: true, ExportTypes: true})
+//	output, err := gen.Generate()
+//	for _, diagnostic := range gen.Diagnostics() { ... }
+//
+// A Generator can be reused; Diagnostics and Types describe the most recent Generate, GenerateFiles or Model call.
+type Generator struct {
+	reflector *openapi31.Reflector
+	options   Options
+
+	diagnostics []Diagnostic
+	types       []EmittedType
+}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is an error or warning about the document, i.e. a schema degraded to unknown in lenient mode
+type Diagnostic struct {
+	Severity Severity
+	SpecError
+}
+
+type TypeKind string
+
+const (
+	TypeKindComponentSchema TypeKind = "componentSchema"
+	TypeKindParam           TypeKind = "param"
+	TypeKindBody            TypeKind = "body"
+	TypeKindRequest         TypeKind = "request"
+	TypeKindResponseData    TypeKind = "responseData"
+	TypeKindResponseError   TypeKind = "responseError"
+
+	// An inline schema hoisted to a named type
+	TypeKindInline TypeKind = "inline"
+)
+
+// EmittedType is a type generated for a component schema, an operation or a hoisted inline schema
+type EmittedType struct {
+	Name string
+	Kind TypeKind
+
+	// JSON Pointer of the component, operation or inline schema the type was generated from, i.e. /components/schemas/Pet or /paths/~1pet/post
+	Pointer string
+
+	// File declaring the type when generating files, i.e. components.d.ts; empty for Generate and Model
+	File string
+}
+
+// Returns an error if the options are invalid, i.e. a naming template without {name} or an unknown quote style.
+// A spec without components or paths generates an empty client.
+func NewGenerator(reflector *openapi31.Reflector, options Options) (*Generator, error) {
+	if reflector == nil || reflector.Spec == nil {
+		return nil, fmt.Errorf("the reflector has no spec")
+	}
+
+	err := options.Naming.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = options.Format.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.EnumObjects && !options.EmitTs {
+		return nil, fmt.Errorf("EnumObjects requires EmitTs, a .d.ts can't have runtime values")
+	}
+
+	err = options.FormatTypes.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mapping := range options.Mappings {
+		err = mapping.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Generator{reflector: reflector, options: options}, nil
+}
+
+// Generate a single module. If options.CollectErrors is set, the output is returned even if there are errors.
+func (gen *Generator) Generate() (string, error) {
+	g := gen.start()
+	output, err := g.generate()
+	gen.finish(g, err)
+	return output, err
+}
+
+// Generate separate modules by file name, see GenerateTypedFetchFiles
+func (gen *Generator) GenerateFiles() (map[string]string, error) {
+	g := gen.start()
+	files, err := g.generateFiles()
+	gen.finish(g, err)
+	return files, err
+}
+
+// The intermediate model of the document, for emitting something other than the TypeScript client.
+// Like Generate, errors are collected in the model (see Operation.Failed) if options.CollectErrors is set.
+func (gen *Generator) Model() (*Model, error) {
+	g := gen.start()
+	model, err := g.buildModel()
+	if err == nil {
+		err = g.errors.orNil()
+	}
+	gen.finish(g, err)
+	return model, err
+}
+
+// Errors and warnings of the last generation, in the order they were found
+func (gen *Generator) Diagnostics() []Diagnostic {
+	return gen.diagnostics
+}
+
+// Component, inline and operation types emitted by the last generation, components first
+func (gen *Generator) Types() []EmittedType {
+	return gen.types
+}
+
+func (gen *Generator) start() *generator {
+	gen.diagnostics = nil
+	gen.types = nil
+
+	options := gen.options
+	options.OnWarning = func(warning *SpecError) {
+		gen.diagnostics = append(gen.diagnostics, Diagnostic{Severity: SeverityWarning, SpecError: *warning})
+		if gen.options.OnWarning != nil {
+			gen.options.OnWarning(warning)
+		}
+	}
+
+	return newGenerator(gen.reflector, options)
+}
+
+func (gen *Generator) finish(g *generator, err error) {
+	if err != nil {
+		for _, specErr := range (SpecErrors{}).append(err) {
+			gen.diagnostics = append(gen.diagnostics, Diagnostic{Severity: SeverityError, SpecError: *specErr})
+		}
+	}
+
+	gen.types = g.emittedTypes()
+}
+
+// Types of the components, inline types and operations of the model, with the file declaring them
+func (g *generator) emittedTypes() []EmittedType {
+	types := []EmittedType{}
+	if g.model == nil {
+		return types
+	}
+
+	add := func(name string, kind TypeKind, pointer string) {
+		types = append(types, EmittedType{Name: name, Kind: kind, Pointer: pointer, File: g.declaredIn[name]})
+	}
+
+	for _, component := range g.model.Components {
+		add(component.Name, TypeKindComponentSchema, component.Pointer)
+	}
+
+	for _, inlineType := range g.model.InlineTypes {
+		add(inlineType.Name, TypeKindInline, inlineType.Pointer)
+	}
+
+	for _, op := range g.model.Operations {
+		if op.Failed {
+			continue
+		}
+
+		if op.Params != nil {
+			add(op.Params.TypeName, TypeKindParam, op.Pointer)
+		}
+		if op.Body != nil {
+			add(op.Body.TypeName, TypeKindBody, op.Pointer)
+		}
+		add(op.RequestTypeName, TypeKindRequest, op.Pointer)
+		add(op.ResponseData.TypeName, TypeKindResponseData, op.Pointer)
+		add(op.ResponseError.TypeName, TypeKindResponseError, op.Pointer)
+	}
+
+	return types
+}
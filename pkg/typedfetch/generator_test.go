@@ -0,0 +1,257 @@
+package typedfetch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Build the model of a document with the given component schemas and paths (in YAML)
+func buildTestModel(t *testing.T, schemas, paths string, options Options) *Model {
+	t.Helper()
+
+	if paths == "" {
+		paths = "{}"
+	}
+	spec := "openapi: 3.1.0\ninfo: {title: test, version: '1'}\npaths: " + paths + "\ncomponents:\n  schemas: " + schemas + "\n"
+
+	reflector, err := loader.LoadBytes([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generator, err := NewGenerator(reflector, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := generator.Model()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// The component generated for the schema #/components/schemas/<schemaName>
+func testComponent(t *testing.T, model *Model, schemaName string) *NamedType {
+	t.Helper()
+
+	for _, component := range model.Components {
+		if component.SchemaName == schemaName {
+			return component
+		}
+	}
+
+	t.Fatalf("missing the component %s", schemaName)
+	return nil
+}
+
+// JSON of a type, so that a mismatch is readable
+func typeJson(t Type) string {
+	bytes, _ := json.Marshal(t)
+	return string(bytes)
+}
+
+func TestModelComponents(t *testing.T) {
+	stringType := &KeywordType{Keyword: "string"}
+	numberType := &KeywordType{Keyword: "number"}
+
+	tests := []struct {
+		name    string
+		schemas string
+
+		// Type of the component A
+		want Type
+	}{
+		{
+			name:    "keyword",
+			schemas: "{A: {type: integer}}",
+			want:    numberType,
+		},
+		{
+			name:    "enum",
+			schemas: "{A: {type: string, enum: [dog, cat]}}",
+			want:    &UnionType{Members: []Type{&LiteralType{Value: "dog"}, &LiteralType{Value: "cat"}}},
+		},
+		{
+			name:    "type list",
+			schemas: "{A: {type: [string, 'null']}}",
+			want:    &UnionType{Members: []Type{stringType, &KeywordType{Keyword: "null"}}},
+		},
+		{
+			name:    "array of references",
+			schemas: "{A: {type: array, items: {$ref: '#/components/schemas/B'}}, B: {type: string}}",
+			want:    &ArrayType{Items: &ReferenceType{Name: "ComponentSchemaB", Ref: "#/components/schemas/B"}},
+		},
+		{
+			name:    "object",
+			schemas: "{A: {type: object, required: [id], properties: {id: {type: integer}, name: {type: string, description: The name}}}}",
+			want: &ObjectType{Properties: []*Property{
+				{Name: "id", Type: numberType},
+				{Name: "name", Optional: true, Description: "The name", Type: stringType},
+			}},
+		},
+		{
+			name:    "dictionary",
+			schemas: "{A: {type: object, additionalProperties: {type: string}}}",
+			want:    &ObjectType{IndexSignature: stringType},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, test.schemas, "", Options{})
+
+			component := testComponent(t, model, "A")
+			if component.Name != "ComponentSchemaA" || component.Pointer != "/components/schemas/A" {
+				t.Errorf("name = %s, pointer = %s", component.Name, component.Pointer)
+			}
+			if !reflect.DeepEqual(component.Type, test.want) {
+				t.Errorf("type = %s, want %s", typeJson(component.Type), typeJson(test.want))
+			}
+		})
+	}
+}
+
+func TestModelOperations(t *testing.T) {
+	paths := `
+    /pets/{petId}:
+      get:
+        parameters:
+          - {name: petId, in: path, required: true, schema: {type: integer}}
+          - {name: tags, in: query, schema: {type: array, items: {type: string}}}
+        responses:
+          '200': {description: ok, content: {application/json: {schema: {$ref: '#/components/schemas/Pet'}}}}
+          '404': {description: not found}
+      delete:
+        operationId: deletePet
+        parameters:
+          - {name: petId, in: path, required: true, schema: {type: integer}}
+        requestBody:
+          content: {application/json: {schema: {$ref: '#/components/schemas/Pet'}}}
+        responses:
+          '204': {description: deleted}`
+
+	useOperationId := true
+	model := buildTestModel(t, "{Pet: {type: object, properties: {name: {type: string}}}}", paths, Options{Naming: Naming{UseOperationId: &useOperationId}})
+
+	if len(model.Operations) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(model.Operations))
+	}
+
+	tests := []struct {
+		method string
+
+		name    string
+		params  []string
+		body    bool
+		data    Type
+		pointer string
+	}{
+		{
+			method:  "DELETE",
+			name:    "DeletePet",
+			params:  []string{"path:petId"},
+			body:    true,
+			pointer: "/paths/~1pets~1{petId}/delete",
+		},
+		{
+			method:  "GET",
+			name:    "GetPetsPetId",
+			params:  []string{"path:petId", "query:tags"},
+			data:    &ReferenceType{Name: "ComponentSchemaPet", Ref: "#/components/schemas/Pet"},
+			pointer: "/paths/~1pets~1{petId}/get",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			var operation *Operation
+			for _, o := range model.Operations {
+				if o.Method == test.method {
+					operation = o
+				}
+			}
+			if operation == nil {
+				t.Fatalf("missing operation %s", test.method)
+			}
+
+			if operation.Name != test.name || operation.Pointer != test.pointer {
+				t.Errorf("name = %s, pointer = %s, want %s, %s", operation.Name, operation.Pointer, test.name, test.pointer)
+			}
+
+			params := []string{}
+			if operation.Params != nil {
+				for _, group := range operation.Params.Groups {
+					for _, param := range group.Params {
+						params = append(params, group.In+":"+param.Name)
+					}
+				}
+			}
+			if !reflect.DeepEqual(params, test.params) {
+				t.Errorf("params = %v, want %v", params, test.params)
+			}
+
+			if (operation.Body != nil) != test.body {
+				t.Errorf("body = %v, want %v", operation.Body != nil, test.body)
+			}
+
+			var data Type
+			if operation.ResponseData != nil {
+				data = operation.ResponseData.Type
+			}
+			if !reflect.DeepEqual(data, test.data) {
+				t.Errorf("response data = %s, want %s", typeJson(data), typeJson(test.data))
+			}
+		})
+	}
+}
+
+// Library callers build the reflector themselves, where components and paths are nil until set
+func TestGeneratorEmptySpec(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *openapi31.Spec
+	}{
+		{name: "no components or paths", spec: &openapi31.Spec{Openapi: "3.1.0"}},
+		{name: "no components", spec: &openapi31.Spec{Openapi: "3.1.0", Paths: &openapi31.Paths{}}},
+		{name: "no paths", spec: &openapi31.Spec{Openapi: "3.1.0", Components: &openapi31.Components{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reflector := openapi31.NewReflector()
+			reflector.Spec = test.spec
+
+			generator, err := NewGenerator(reflector, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			model, err := generator.Model()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(model.Components) != 0 || len(model.Operations) != 0 {
+				t.Errorf("expected an empty model, got %d components and %d operations", len(model.Components), len(model.Operations))
+			}
+
+			output, err := generator.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(output, "Client") {
+				t.Errorf("expected the client, got\n%s", output)
+			}
+		})
+	}
+
+	_, err := NewGenerator(&openapi31.Reflector{}, Options{})
+	if err == nil {
+		t.Error("expected an error for a reflector without a spec")
+	}
+}
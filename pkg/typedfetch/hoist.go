@@ -0,0 +1,112 @@
+package typedfetch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The named type whose schema is being translated. Inline schemas hoisted out of it are named after it and their
+// location within it, i.e. the status property of ComponentSchemaPet -> ComponentSchemaPetStatus.
+type hoistOwner struct {
+	name    string
+	pointer string
+
+	// Hoist the root schema too, i.e. a parameter's schema, which isn't a named type by itself
+	hoistRoot bool
+}
+
+// Whether the schema at pointer is inline rather than the root of a named type
+func (g *generator) isInline(pointer string) bool {
+	return g.owner.hoistRoot || pointer != g.owner.pointer
+}
+
+// Declare t as a named type and reference it instead; hoisting the same schema again returns the same name
+func (g *generator) hoist(t Type, pointer string) Type {
+	if name, ok := g.hoistedNames[pointer]; ok {
+		return &ReferenceType{Name: name}
+	}
+
+	name := g.owner.name
+	for _, word := range hoistedNameWords(strings.TrimPrefix(pointer, g.owner.pointer)) {
+		name += toIdentifier(word, CasingPascal)
+	}
+	name = uniqueName(name, g.usedTypeNames())
+
+	g.hoistedNames[pointer] = name
+	g.inlineTypes = append(g.inlineTypes, &NamedType{Name: name, Pointer: pointer, Type: t})
+	return &ReferenceType{Name: name}
+}
+
+// The words naming a location within a schema, i.e. /properties/tags/items -> [tags, item] and /oneOf/0 -> [option1]
+func hoistedNameWords(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+
+	tokens := refTokens("#" + pointer)
+	words := []string{}
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "schema":
+		case "properties":
+			if i+1 < len(tokens) {
+				words = append(words, tokens[i+1])
+				i++
+			}
+		case "content":
+			// The content type
+			i++
+		case "items":
+			words = append(words, "item")
+		case "additionalProperties":
+			words = append(words, "value")
+		case "patternProperties":
+			// The pattern
+			words = append(words, "value")
+			i++
+		case "propertyNames":
+			words = append(words, "key")
+		case "allOf", "anyOf", "oneOf":
+			if i+1 < len(tokens) {
+				index, _ := strconv.Atoi(tokens[i+1])
+				words = append(words, fmt.Sprintf("option%d", index+1))
+				i++
+			}
+		default:
+			words = append(words, tokens[i])
+		}
+	}
+	return words
+}
+
+// Every type name declared so far, so that hoisted names don't clash with them
+func (g *generator) usedTypeNames() map[string]bool {
+	if g.typeNames != nil {
+		return g.typeNames
+	}
+
+	g.typeNames = g.reservedTypeNames()
+	for _, component := range sortedMapKeys(specComponents(g.reflector).Schemas) {
+		g.typeNames[g.componentSchemaTypeName(component)] = true
+	}
+	return g.typeNames
+}
+
+func (p *tsPrinter) printInlineTypes(inlineTypes []*NamedType) []string {
+	if len(inlineTypes) == 0 {
+		return []string{}
+	}
+
+	lines := []string{
+		"// Inline types",
+		"",
+	}
+
+	for _, inlineType := range inlineTypes {
+		lines = append(lines, p.printNamedType(buildDocString(inlineType.Description, inlineType.Example), inlineType.Name, inlineType.Type)...)
+		lines = append(lines, "")
+	}
+
+	return lines
+}
@@ -0,0 +1,155 @@
+package typedfetch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Hook customizes the types generated for schemas
+type Hook interface {
+	// Called for every schema node before it's translated; return nil to keep the generated type.
+	// The members of a type list, i.e. type: [string, "null"], are passed again one type at a time.
+	TranslateSchema(node SchemaNode) (*TypeOverride, error)
+}
+
+type HookFunc func(node SchemaNode) (*TypeOverride, error)
+
+func (f HookFunc) TranslateSchema(node SchemaNode) (*TypeOverride, error) {
+	return f(node)
+}
+
+type SchemaNode struct {
+	Schema map[string]any
+
+	// JSON Pointer of the schema, i.e. /components/schemas/Pet/properties/createdAt
+	Pointer string
+}
+
+// Replaces the type generated for a schema
+type TypeOverride struct {
+	// i.e. IsoDateString
+	TypeScript string
+
+	// Types TypeScript uses that must be imported, i.e. {Name: "IsoDateString", From: "./brands"}
+	Imports []Import
+}
+
+// import type { Name } from "From". A relative From is relative to the output file (or output directory).
+type Import struct {
+	Name string `yaml:"name"`
+	From string `yaml:"from"`
+}
+
+// Mapping overrides the type of every schema with the given format or vendor extension, i.e.
+// {Format: "uuid", Type: "Uuid", Import: "./brands"}. Type may contain {value}, replaced by the value of the extension,
+// i.e. {Extension: "x-brand", Type: "{value}"}.
+type Mapping struct {
+	Format    string `yaml:"format"`
+	Extension string `yaml:"extension"`
+
+	Type string `yaml:"type"`
+
+	// Module to import Type from, if it isn't global
+	Import string `yaml:"import"`
+}
+
+func (m Mapping) Validate() error {
+	if (m.Format == "") == (m.Extension == "") {
+		return fmt.Errorf("mapping: expected either format or extension: %+v", m)
+	}
+
+	if m.Extension != "" && !strings.HasPrefix(m.Extension, "x-") {
+		return fmt.Errorf("mapping: extension %q must start with x-", m.Extension)
+	}
+
+	if m.Type == "" {
+		return fmt.Errorf("mapping: type is required: %+v", m)
+	}
+
+	// {value} is only known per schema, see mappingHook
+	tsType := m.Type
+	if m.Extension != "" {
+		tsType = strings.ReplaceAll(tsType, "{value}", "Value")
+	}
+	if m.Import != "" && !identifierNameRegexp.MatchString(tsType) {
+		return fmt.Errorf("mapping: type %q must be a single identifier to be imported", m.Type)
+	}
+
+	return nil
+}
+
+// Applies the first matching mapping
+type mappingHook []Mapping
+
+func (mappings mappingHook) TranslateSchema(node SchemaNode) (*TypeOverride, error) {
+	// A mapped type list keeps its null member, i.e. IsoDateString | null
+	switch t := node.Schema["type"].(type) {
+	case []any:
+		return nil, nil
+	case string:
+		if t == "null" {
+			return nil, nil
+		}
+	}
+
+	for _, mapping := range mappings {
+		tsType := mapping.Type
+		if mapping.Format != "" {
+			if format, _ := node.Schema["format"].(string); format != mapping.Format {
+				continue
+			}
+		} else {
+			value, ok := node.Schema[mapping.Extension]
+			if !ok {
+				continue
+			}
+			tsType = strings.ReplaceAll(tsType, "{value}", fmt.Sprint(value))
+		}
+
+		override := &TypeOverride{TypeScript: tsType}
+		if mapping.Import != "" {
+			if !identifierNameRegexp.MatchString(tsType) {
+				return nil, fmt.Errorf("mapping: type %q must be a single identifier to be imported", tsType)
+			}
+			override.Imports = []Import{{Name: tsType, From: mapping.Import}}
+		}
+		return override, nil
+	}
+
+	return nil, nil
+}
+
+// The override of the first hook that returns one, or nil
+func (g *generator) overrideType(schema map[string]any, pointer string) (Type, error) {
+	for _, hook := range g.hooks {
+		override, err := hook.TranslateSchema(SchemaNode{Schema: schema, Pointer: pointer})
+		if err != nil {
+			return nil, specErrorAt(pointer, "%s", err)
+		}
+
+		if override != nil {
+			for _, i := range override.Imports {
+				g.imports[i] = true
+			}
+			return &RawType{TypeScript: override.TypeScript, Imports: override.Imports}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Sorted by module, then name
+func sortedImports(imports map[Import]bool) []Import {
+	sorted := []Import{}
+	for i := range imports {
+		sorted = append(sorted, i)
+	}
+	sort.Slice(sorted, func(a, b int) bool {
+		if sorted[a].From != sorted[b].From {
+			return sorted[a].From < sorted[b].From
+		}
+		return sorted[a].Name < sorted[b].Name
+	})
+	return sorted
+}
@@ -0,0 +1,108 @@
+package typedfetch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeOverrides(t *testing.T) {
+	uuid := Mapping{Format: "uuid", Type: "Uuid", Import: "./brands"}
+	uuidType := &RawType{TypeScript: "Uuid", Imports: []Import{{Name: "Uuid", From: "./brands"}}}
+
+	tests := []struct {
+		name    string
+		schemas string
+		options Options
+
+		// Type of the component A
+		want Type
+	}{
+		{
+			name:    "format",
+			schemas: "{A: {type: string, format: uuid}}",
+			options: Options{Mappings: []Mapping{uuid}},
+			want:    uuidType,
+		},
+		{
+			name:    "extension value",
+			schemas: "{A: {type: string, x-brand: UserId}}",
+			options: Options{Mappings: []Mapping{{Extension: "x-brand", Type: "Brand<'{value}'>"}}},
+			want:    &RawType{TypeScript: "Brand<'UserId'>"},
+		},
+		{
+			name:    "imported extension value",
+			schemas: "{A: {type: string, x-brand: UserId}}",
+			options: Options{Mappings: []Mapping{{Extension: "x-brand", Type: "{value}", Import: "./ids"}}},
+			want:    &RawType{TypeScript: "UserId", Imports: []Import{{Name: "UserId", From: "./ids"}}},
+		},
+		{
+			name:    "type list keeps null",
+			schemas: "{A: {type: [string, 'null'], format: uuid}}",
+			options: Options{Mappings: []Mapping{uuid}},
+			want:    &UnionType{Members: []Type{uuidType, &KeywordType{Keyword: "null"}}},
+		},
+		{
+			name:    "first mapping",
+			schemas: "{A: {type: string, format: uuid}}",
+			options: Options{Mappings: []Mapping{uuid, {Format: "uuid", Type: "string"}}},
+			want:    uuidType,
+		},
+		{
+			name:    "no match",
+			schemas: "{A: {type: string, format: email}}",
+			options: Options{Mappings: []Mapping{uuid}},
+			want:    &KeywordType{Keyword: "string"},
+		},
+		{
+			name:    "hook before mappings",
+			schemas: "{A: {type: string, format: uuid}}",
+			options: Options{
+				Mappings: []Mapping{uuid},
+				Hooks: []Hook{HookFunc(func(node SchemaNode) (*TypeOverride, error) {
+					if node.Pointer != "/components/schemas/A" {
+						return nil, nil
+					}
+					return &TypeOverride{TypeScript: "Id"}, nil
+				})},
+			},
+			want: &RawType{TypeScript: "Id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, test.schemas, "", test.options)
+
+			component := testComponent(t, model, "A")
+			if !reflect.DeepEqual(component.Type, test.want) {
+				t.Errorf("type = %s, want %s", typeJson(component.Type), typeJson(test.want))
+			}
+		})
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping Mapping
+		wantErr bool
+	}{
+		{name: "format", mapping: Mapping{Format: "uuid", Type: "Uuid", Import: "./brands"}},
+		{name: "extension", mapping: Mapping{Extension: "x-brand", Type: "{value}", Import: "./brands"}},
+		{name: "global type", mapping: Mapping{Format: "date", Type: "Date | string"}},
+		{name: "neither", mapping: Mapping{Type: "Uuid"}, wantErr: true},
+		{name: "both", mapping: Mapping{Format: "uuid", Extension: "x-brand", Type: "Uuid"}, wantErr: true},
+		{name: "extension without x-", mapping: Mapping{Extension: "brand", Type: "Uuid"}, wantErr: true},
+		{name: "no type", mapping: Mapping{Format: "uuid"}, wantErr: true},
+		{name: "imported expression", mapping: Mapping{Format: "uuid", Type: "Uuid | null", Import: "./brands"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.mapping.Validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("error = %v, want an error: %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package typedfetch
+
+// Model is the intermediate representation of a document: everything an emitter needs, resolved from the
+// spec once. Names are final (naming options applied), references are resolved, and every node keeps the
+// JSON Pointer it was built from. The TypeScript emitter only reads the Model.
+type Model struct {
+	// Component schemas in name order, after filtering and tree shaking
+	Components []*NamedType
+
+	// Inline schemas hoisted to named types (see Options.EnumObjects and HoistInlineTypes), in the order they were found
+	InlineTypes []*NamedType
+
+	// Selected operations, sorted by path
+	Operations []*Operation
+
+	// Every import of the RawTypes, sorted by module
+	Imports []Import
+
+	// Branded strings the types reference, sorted by name (see FormatTypes)
+	Brands []Brand
+}
+
+// NamedType is a component schema, or an inline schema hoisted to a named type (without a SchemaName)
+type NamedType struct {
+	// TypeScript name, i.e. ComponentSchemaPet
+	Name string
+
+	// Name in the document, i.e. Pet
+	SchemaName string
+
+	Pointer     string
+	Description string
+	Example     string
+
+	// Nil if the schema couldn't be translated (only when collecting errors)
+	Type Type
+}
+
+type Operation struct {
+	Method      string
+	Path        string
+	OperationId string
+	Tags        []string
+	Pointer     string
+
+	// The {name} of the operation's types, i.e. GetPetPetId
+	Name string
+
+	// Nil if the operation has no parameters / request body
+	Params *Params
+	Body   *Body
+
+	RequestTypeName string
+
+	// The first 2xx response (or default), and the first 4xx/5xx response (or default)
+	ResponseData  *Response
+	ResponseError *Response
+
+	// The operation couldn't be generated (only when collecting errors); nothing but the fields above Params is set
+	Failed bool
+}
+
+type Params struct {
+	TypeName string
+
+	// Whether any parameter is required
+	Required bool
+
+	// Parameters grouped by location (path, query, header, cookie), in the order the locations first appear
+	Groups []*ParamGroup
+}
+
+type ParamGroup struct {
+	In       string
+	Required bool
+	Params   []*Param
+}
+
+type Param struct {
+	Name        string
+	Required    bool
+	Description string
+	Pointer     string
+	Type        Type
+}
+
+type Body struct {
+	TypeName    string
+	Required    bool
+	ContentType string
+	Pointer     string
+	Type        Type
+}
+
+type Response struct {
+	TypeName    string
+	ContentType string
+	Pointer     string
+
+	// Nil for a response without content
+	Type Type
+}
+
+// Type is a type expression: one of *KeywordType, *ReferenceType, *LiteralType, *ArrayType, *ObjectType,
+// *UnionType, *IntersectionType, *EnumType or *RawType
+type Type interface {
+	isType()
+}
+
+// any, unknown, string, number, boolean or null
+type KeywordType struct {
+	Keyword string
+
+	// Explains a degraded type in lenient mode, i.e. "typed-fetch: invalid type: wat"
+	Comment string
+}
+
+// A named type, either a component or a global type like Blob
+type ReferenceType struct {
+	Name          string
+	TypeArguments []Type
+
+	// The $ref of a component, i.e. #/components/schemas/Pet
+	Ref string
+}
+
+// A string, number or boolean literal
+type LiteralType struct {
+	Value any
+}
+
+type ArrayType struct {
+	Items Type
+}
+
+type ObjectType struct {
+	Properties []*Property
+
+	// patternProperties whose pattern is a literal prefix ([key: `x-${string}`]: T), in pattern order
+	PrefixSignatures []*PrefixSignature
+
+	// Type of additional properties and of the other patternProperties ([key: string]: T); nil if there are none
+	IndexSignature Type
+
+	// additionalProperties: false. TypeScript object types can't forbid other properties, so this only leaves out
+	// the index signature.
+	Closed bool
+}
+
+// The properties whose names start with Prefix, i.e. patternProperties: {"^x-": T}
+type PrefixSignature struct {
+	Prefix string
+	Type   Type
+}
+
+type Property struct {
+	Name        string
+	Optional    bool
+	Description string
+	Example     string
+	Type        Type
+}
+
+type UnionType struct {
+	Members []Type
+}
+
+type IntersectionType struct {
+	Members []Type
+}
+
+// An enum with named members (x-enum-varnames, or named after the values with Options.EnumObjects).
+// Printed as a const object and the union of its values; inline ones are hoisted to named types.
+type EnumType struct {
+	Members []*EnumMember
+}
+
+type EnumMember struct {
+	Name string
+
+	// A string or a number
+	Value any
+
+	// From x-enum-descriptions
+	Description string
+}
+
+// TypeScript given verbatim, i.e. by a type mapping or a Hook
+type RawType struct {
+	TypeScript string
+	Imports    []Import
+}
+
+func (*KeywordType) isType()      {}
+func (*ReferenceType) isType()    {}
+func (*LiteralType) isType()      {}
+func (*ArrayType) isType()        {}
+func (*ObjectType) isType()       {}
+func (*UnionType) isType()        {}
+func (*IntersectionType) isType() {}
+func (*EnumType) isType()         {}
+func (*RawType) isType()          {}
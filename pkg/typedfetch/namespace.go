@@ -0,0 +1,109 @@
+package typedfetch
+
+import (
+	"fmt"
+)
+
+type namespaceAlias struct {
+	alias    string
+	typeName string
+}
+
+type operationNamespace struct {
+	name    string
+	aliases []namespaceAlias
+}
+
+// Aliases of the generated types grouped by namespace, i.e. Components.Schemas.Pet and Operations.GetPetById.Response.
+// declared are the names declared so far, which the escape aliases must not clash with.
+func (p *tsPrinter) printNamespaces(model *Model, declared map[string]bool) []string {
+	// Every name declared inside the namespaces, which would shadow a top level type of the same name
+	shadowing := map[string]bool{"Components": true, "Schemas": true, "Operations": true}
+
+	componentAliases := []namespaceAlias{}
+	usedComponents := map[string]bool{}
+	for _, component := range model.Components {
+		alias := uniqueName(toIdentifier(component.SchemaName, CasingPascal), usedComponents)
+		componentAliases = append(componentAliases, namespaceAlias{alias, component.Name})
+		shadowing[alias] = true
+	}
+
+	operations := []operationNamespace{}
+	usedOperations := map[string]bool{}
+	for _, op := range model.Operations {
+		// Operations that failed to generate have no types
+		if op.Failed {
+			continue
+		}
+
+		aliases := []namespaceAlias{}
+		if op.Params != nil {
+			aliases = append(aliases, namespaceAlias{"Params", op.Params.TypeName})
+		}
+		if op.Body != nil {
+			aliases = append(aliases, namespaceAlias{"Body", op.Body.TypeName})
+		}
+		aliases = append(aliases,
+			namespaceAlias{"Request", op.RequestTypeName},
+			namespaceAlias{"Response", op.ResponseData.TypeName},
+			namespaceAlias{"Error", op.ResponseError.TypeName},
+		)
+		for _, a := range aliases {
+			shadowing[a.alias] = true
+		}
+
+		name := uniqueName(getOperationNamespaceName(op), usedOperations)
+		operations = append(operations, operationNamespace{name, aliases})
+		shadowing[name] = true
+	}
+
+	lines := []string{
+		"// Namespaces",
+		"",
+	}
+
+	// Shadowed types are referenced through a top level alias instead, i.e. Components.Schemas.Pet = _Pet = Pet
+	escaped := map[string]string{}
+	escape := func(typeName string) string {
+		if !shadowing[typeName] {
+			return typeName
+		}
+
+		if _, ok := escaped[typeName]; !ok {
+			escaped[typeName] = uniqueName("_"+typeName, declared)
+			lines = append(lines, p.typeAlias("", escaped[typeName], tsReferenceTo(typeName))...)
+		}
+		return escaped[typeName]
+	}
+
+	namespaceLines := []string{
+		"export namespace Components {",
+		p.indent(1) + "export namespace Schemas {",
+	}
+	for _, a := range componentAliases {
+		namespaceLines = append(namespaceLines, fmt.Sprintf("%sexport type %s = %s;", p.indent(2), a.alias, escape(a.typeName)))
+	}
+	namespaceLines = append(namespaceLines, p.indent(1)+"}", "}", "", "export namespace Operations {")
+
+	for _, operation := range operations {
+		namespaceLines = append(namespaceLines, fmt.Sprintf("%sexport namespace %s {", p.indent(1), operation.name))
+		for _, a := range operation.aliases {
+			namespaceLines = append(namespaceLines, fmt.Sprintf("%sexport type %s = %s;", p.indent(2), a.alias, escape(a.typeName)))
+		}
+		namespaceLines = append(namespaceLines, p.indent(1)+"}")
+	}
+	namespaceLines = append(namespaceLines, "}", "")
+
+	if len(escaped) > 0 {
+		lines = append(lines, "")
+	}
+	return append(lines, namespaceLines...)
+}
+
+// The operationId, i.e. getPetById -> GetPetById, or the endpoint if there's none, i.e. GetPetPetId
+func getOperationNamespaceName(op *Operation) string {
+	if op.OperationId != "" {
+		return toIdentifier(op.OperationId, CasingPascal)
+	}
+	return toIdentifier(getUniqueEndpointName(op.Method, op.Path), CasingPascal)
+}
@@ -0,0 +1,293 @@
+package typedfetch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type Casing string
+
+const (
+	// First letter of every word uppercased, separators removed, i.e. pet.status -> PetStatus (default)
+	CasingPascal Casing = "pascal"
+
+	// Like pascal, but with the first letter lowercased, i.e. Pet.Status -> petStatus
+	CasingCamel Casing = "camel"
+
+	// The original name, with characters that aren't valid in an identifier replaced with _, i.e. pet.status -> pet_status
+	CasingPreserve Casing = "preserve"
+)
+
+// Names of the generated types. Templates are expanded with {name} replaced by the component name
+// (for ComponentSchema) or the operation name (for the rest). Empty fields use the defaults.
+type Naming struct {
+	ComponentSchema string `yaml:"componentSchema"` // default ComponentSchema{name}
+	Param           string `yaml:"param"`           // default Param{name}
+	Body            string `yaml:"body"`            // default Body{name}
+	Request         string `yaml:"request"`         // default Request{name}
+	ResponseData    string `yaml:"responseData"`    // default ResponseData{name}
+	ResponseError   string `yaml:"responseError"`   // default ResponseError{name}
+
+	// How {name} is cased, see Casing
+	Casing Casing `yaml:"casing"`
+
+	// Name operations after their operationId when they have one, i.e. getPetById -> BodyGetPetById,
+	// rather than after their method and path, i.e. BodyGetPetPetId
+	UseOperationId *bool `yaml:"useOperationId"`
+}
+
+var identifierSeparatorRegexp = regexp.MustCompile(`[^A-Za-z0-9_$]+`)
+
+// Names a type can't be declared with: reserved words and the predefined types
+var typeKeywords = []string{
+	"any", "await", "bigint", "boolean", "break", "case", "catch", "class", "const", "continue", "debugger", "default",
+	"delete", "do", "else", "enum", "export", "extends", "false", "finally", "for", "function", "if", "implements",
+	"import", "in", "infer", "instanceof", "interface", "is", "keyof", "let", "never", "new", "null", "number", "object",
+	"package", "private", "protected", "public", "readonly", "return", "static", "string", "super", "switch", "symbol",
+	"this", "throw", "true", "try", "type", "typeof", "undefined", "unique", "unknown", "var", "void", "while", "with",
+	"yield",
+}
+
+// Global types the generated output references, which a declaration of the same name would shadow
+var globalTypeNames = []string{
+	"ArrayBuffer", "Blob", "BodyInit", "Date", "Exclude", "File", "Omit", "Partial", "Pick", "Record", "RequestInit",
+	"Response",
+}
+
+func (n Naming) withDefaults() Naming {
+	defaults := []struct {
+		template *string
+		fallback string
+	}{
+		{&n.ComponentSchema, "ComponentSchema{name}"},
+		{&n.Param, "Param{name}"},
+		{&n.Body, "Body{name}"},
+		{&n.Request, "Request{name}"},
+		{&n.ResponseData, "ResponseData{name}"},
+		{&n.ResponseError, "ResponseError{name}"},
+	}
+	for _, d := range defaults {
+		if *d.template == "" {
+			*d.template = d.fallback
+		}
+	}
+
+	if n.Casing == "" {
+		n.Casing = CasingPascal
+	}
+	return n
+}
+
+// Every template needs {name}, and the operation templates must differ so their types don't clash
+func (n Naming) Validate() error {
+	n = n.withDefaults()
+
+	switch n.Casing {
+	case CasingPascal, CasingCamel, CasingPreserve:
+	default:
+		return fmt.Errorf("naming: unknown casing %q (expected pascal, camel or preserve)", n.Casing)
+	}
+
+	templates := map[string]string{
+		"componentSchema": n.ComponentSchema,
+		"param":           n.Param,
+		"body":            n.Body,
+		"request":         n.Request,
+		"responseData":    n.ResponseData,
+		"responseError":   n.ResponseError,
+	}
+
+	seen := map[string]string{}
+	for _, field := range sortedMapKeys(templates) {
+		template := templates[field]
+		if !strings.Contains(template, "{name}") {
+			return fmt.Errorf("naming: %s template %q doesn't contain {name}", field, template)
+		}
+
+		if other, ok := seen[template]; ok && field != "componentSchema" && other != "componentSchema" {
+			return fmt.Errorf("naming: %s and %s templates are both %q", other, field, template)
+		}
+		seen[template] = field
+	}
+
+	return nil
+}
+
+// Make name a valid TypeScript identifier, i.e. (pet.status, pascal) -> PetStatus
+func toIdentifier(name string, casing Casing) string {
+	words := identifierSeparatorRegexp.Split(name, -1)
+
+	identifier := ""
+	switch casing {
+	case CasingPreserve:
+		identifier = strings.Join(words, "_")
+	default:
+		for _, word := range words {
+			identifier += capitalize(word)
+		}
+		if casing == CasingCamel {
+			identifier = lowercaseFirst(identifier)
+		}
+	}
+
+	if identifier == "" || unicode.IsDigit(rune(identifier[0])) {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
+
+func lowercaseFirst(s string) string {
+	if len(s) < 1 {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// Append a number to name until it isn't in used, then mark it used
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func expandTemplate(template, name string) string {
+	return toIdentifier(strings.ReplaceAll(template, "{name}", name), CasingPreserve)
+}
+
+// Keywords, the global types the output references, the names of its fixed declarations (Client,
+// RequestInitExtended, the brands, ...) and of the operation types, which no component or hoisted type may take
+func (g *generator) reservedTypeNames() map[string]bool {
+	reserved := map[string]bool{}
+	for _, name := range append(append([]string{}, typeKeywords...), globalTypeNames...) {
+		reserved[name] = true
+	}
+	for _, name := range []string{
+		"Client", "ClientMethod", "RequestInitExtended", "DataResponse", "ErrorResponse", "FetchResponse", "OptionalKeys", "Branded",
+	} {
+		reserved[name] = true
+	}
+
+	for _, m := range getPathItemMethods(&openapi31.PathItem{}) {
+		reserved[getLookupTypeName(m.Method)] = true
+	}
+
+	for _, name := range brandedFormats {
+		reserved[name] = true
+	}
+	for _, name := range brandedDateFormats {
+		reserved[name] = true
+	}
+
+	for _, path := range sortedMapKeys(specPaths(g.reflector)) {
+		item := specPaths(g.reflector)[path]
+		for _, m := range getPathItemMethods(&item) {
+			if m.Operation == nil {
+				continue
+			}
+
+			for _, name := range []string{
+				g.requestParamTypeName(m.Method, path),
+				g.requestBodyTypeName(m.Method, path),
+				g.requestTypeName(m.Method, path),
+				g.responseDataTypeName(m.Method, path),
+				g.responseErrTypeName(m.Method, path),
+			} {
+				reserved[name] = true
+			}
+		}
+	}
+
+	return reserved
+}
+
+// Type name of each component schema, deduplicated in case sanitizing made two names equal,
+// and so that it doesn't clash with a reserved name (see reservedTypeNames)
+func (g *generator) componentSchemaTypeName(component string) string {
+	if g.componentNames == nil {
+		g.componentNames = map[string]string{}
+		used := g.reservedTypeNames()
+		for _, name := range sortedMapKeys(specComponents(g.reflector).Schemas) {
+			g.componentNames[name] = uniqueName(expandTemplate(g.naming.ComponentSchema, toIdentifier(name, g.naming.Casing)), used)
+		}
+	}
+
+	if typeName, ok := g.componentNames[component]; ok {
+		return typeName
+	}
+
+	// Dangling reference, reported elsewhere
+	return expandTemplate(g.naming.ComponentSchema, toIdentifier(component, g.naming.Casing))
+}
+
+// The {name} of an operation's types, deduplicated like componentSchemaTypeName
+func (g *generator) operationName(method, path string) string {
+	if g.operationNames == nil {
+		g.operationNames = map[string]string{}
+		used := map[string]bool{}
+		for _, operationPath := range sortedMapKeys(specPaths(g.reflector)) {
+			item := specPaths(g.reflector)[operationPath]
+			for _, m := range getPathItemMethods(&item) {
+				if m.Operation == nil {
+					continue
+				}
+
+				name := getUniqueEndpointName(m.Method, operationPath)
+				if g.naming.UseOperationId != nil && *g.naming.UseOperationId && m.Operation.ID != nil {
+					name = *m.Operation.ID
+				}
+				name = toIdentifier(name, g.naming.Casing)
+
+				// Like uniqueName, but none of the operation's types may take a keyword or a global name either
+				unique := name
+				for i := 2; used[unique] || g.operationNameReserved(unique); i++ {
+					unique = fmt.Sprintf("%s%d", name, i)
+				}
+				used[unique] = true
+
+				g.operationNames[getUniqueEndpointName(m.Method, operationPath)] = unique
+			}
+		}
+	}
+
+	return g.operationNames[getUniqueEndpointName(method, path)]
+}
+
+// Whether any of the types of an operation named name would be a keyword or a global type, i.e. Record with the
+// {name} request template
+func (g *generator) operationNameReserved(name string) bool {
+	for _, template := range []string{g.naming.Param, g.naming.Body, g.naming.Request, g.naming.ResponseData, g.naming.ResponseError} {
+		typeName := expandTemplate(template, name)
+		if itemInSlice(typeKeywords, typeName) || itemInSlice(globalTypeNames, typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) requestTypeName(method, path string) string {
+	return expandTemplate(g.naming.Request, g.operationName(method, path))
+}
+
+func (g *generator) requestParamTypeName(method, path string) string {
+	return expandTemplate(g.naming.Param, g.operationName(method, path))
+}
+
+func (g *generator) requestBodyTypeName(method, path string) string {
+	return expandTemplate(g.naming.Body, g.operationName(method, path))
+}
+
+func (g *generator) responseDataTypeName(method, path string) string {
+	return expandTemplate(g.naming.ResponseData, g.operationName(method, path))
+}
+
+func (g *generator) responseErrTypeName(method, path string) string {
+	return expandTemplate(g.naming.ResponseError, g.operationName(method, path))
+}
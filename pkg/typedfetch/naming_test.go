@@ -0,0 +1,51 @@
+package typedfetch
+
+import "testing"
+
+func TestComponentSchemaTypeName(t *testing.T) {
+	paths := "{/pets: {get: {operationId: pets, responses: {'200': {description: ok}}}}}"
+	useOperationId := true
+	preserve := Naming{ComponentSchema: "{name}", Casing: CasingPreserve, UseOperationId: &useOperationId}
+
+	tests := []struct {
+		name   string
+		naming Naming
+		want   string
+	}{
+		{name: "Pet", naming: preserve, want: "Pet"},
+		{name: "string", naming: preserve, want: "string2"},
+		{name: "type", naming: preserve, want: "type2"},
+		{name: "Record", naming: preserve, want: "Record2"},
+		{name: "File", naming: preserve, want: "File2"},
+		{name: "Response", naming: preserve, want: "Response2"},
+		{name: "Client", naming: preserve, want: "Client2"},
+		{name: "Uuid", naming: preserve, want: "Uuid2"},
+		{name: "ResponseDatapets", naming: preserve, want: "ResponseDatapets2"},
+		{name: "string", naming: Naming{ComponentSchema: "{name}"}, want: "String"},
+		{name: "Record", want: "ComponentSchemaRecord"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := buildTestModel(t, "{"+test.name+": {type: string}}", paths, Options{Naming: test.naming})
+			if got := model.Components[0].Name; got != test.want {
+				t.Errorf("name = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOperationNameReserved(t *testing.T) {
+	paths := "{/a: {get: {operationId: record, responses: {'200': {description: ok}}}}, " +
+		"/b: {get: {operationId: pet, responses: {'200': {description: ok}}}}}"
+
+	useOperationId := true
+	model := buildTestModel(t, "{}", paths, Options{Naming: Naming{Request: "{name}", UseOperationId: &useOperationId}})
+
+	want := map[string]string{"/a": "Record2", "/b": "Pet"}
+	for _, op := range model.Operations {
+		if op.RequestTypeName != want[op.Path] {
+			t.Errorf("%s: request type = %s, want %s", op.Path, op.RequestTypeName, want[op.Path])
+		}
+	}
+}
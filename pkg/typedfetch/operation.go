@@ -6,50 +6,110 @@ import (
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
-func generateOperationTypes(reflector *openapi31.Reflector) ([]string, error) {
-	lines := []string{
-		"// Request/Response types",
-		"",
-	}
+func (g *generator) buildOperations(operations []selectedOperation) ([]*Operation, error) {
+	built := []*Operation{}
+	for _, operation := range operations {
+		method, path := operation.Method, operation.Path
+		op := &Operation{
+			Method:  method,
+			Path:    path,
+			Tags:    operation.Operation.Tags,
+			Pointer: getOperationPointer(method, path),
+			Name:    g.operationName(method, path),
+		}
+		if operation.Operation.ID != nil {
+			op.OperationId = *operation.Operation.ID
+		}
 
-	sortedPaths := sortedMapKeys(reflector.Spec.Paths.MapOfPathItemValues)
-	for _, path := range sortedPaths {
-		item := reflector.Spec.Paths.MapOfPathItemValues[path]
-		methods := getPathItemMethods(&item)
-		for _, method := range methods {
-			if method.Operation == nil {
-				continue
+		err := g.buildOperation(op, operation.Operation)
+		if err != nil {
+			if !g.collect(err) {
+				return nil, err
 			}
 
-			lines = append(lines, fmt.Sprintf("// %s %s", method.Method, path))
+			op.Failed = true
+		}
 
-			// Generate the param type
-			paramInfo, err := getParamInfo(reflector, method.Operation, method.Method, path)
-			if err != nil {
-				return nil, err
-			}
+		built = append(built, op)
+	}
 
-			// Generate the body type
-			bodyInfo, err := getRequestBodyInfo(reflector, method.Operation, method.Method, path)
-			if err != nil {
-				return nil, err
-			}
+	return built, nil
+}
 
-			requestLines, err := generateRequestTypes(method.Method, path, paramInfo, bodyInfo)
-			if err != nil {
-				return nil, err
-			}
-			lines = append(lines, requestLines...)
+// Resolve the parameters, body and responses of op, collecting errors from every part of it.
+// op is only filled in if there are none.
+func (g *generator) buildOperation(op *Operation, operation *openapi31.Operation) error {
+	method, path := op.Method, op.Path
+	errs := SpecErrors{}
 
-			// Generate the response types
-			responseLines, err := generateResponseTypes(reflector, method.Operation, method.Method, path)
-			if err != nil {
-				return nil, err
-			}
-			lines = append(lines, responseLines...)
+	params, err := g.buildParams(operation, method, path)
+	if err != nil {
+		errs = errs.append(err)
+	}
+
+	body, err := g.buildBody(operation, method, path)
+	if err != nil {
+		errs = errs.append(err)
+	}
+
+	responseData, responseError, err := g.buildResponses(operation, method, path)
+	if err != nil {
+		errs = errs.append(err)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	op.Params = params
+	op.Body = body
+	op.RequestTypeName = g.requestTypeName(method, path)
+	op.ResponseData = responseData
+	op.ResponseError = responseError
+	return nil
+}
+
+// Names of the types printOperationTypes declares
+func operationTypeNames(operations []*Operation) []string {
+	names := []string{}
+	for _, op := range operations {
+		if op.Failed {
+			continue
+		}
+
+		if op.Params != nil {
+			names = append(names, op.Params.TypeName)
+		}
+		if op.Body != nil {
+			names = append(names, op.Body.TypeName)
+		}
+		names = append(names, op.RequestTypeName, op.ResponseData.TypeName, op.ResponseError.TypeName)
+	}
+	return names
+}
+
+func (p *tsPrinter) printOperationTypes(operations []*Operation) []string {
+	lines := []string{
+		"// Request/Response types",
+		"",
+	}
+
+	for _, op := range operations {
+		lines = append(lines, fmt.Sprintf("// %s %s", op.Method, op.Path))
+
+		if op.Failed {
+			lines = append(lines, "// typed-fetch: this operation could not be generated, see errors")
 			lines = append(lines, "")
+			continue
 		}
+
+		lines = append(lines, p.printParamType(op.Params)...)
+		lines = append(lines, p.printBodyType(op.Body)...)
+		lines = append(lines, p.printRequestType(op)...)
+		lines = append(lines, p.printResponseType(op.ResponseData)...)
+		lines = append(lines, p.printResponseType(op.ResponseError)...)
+		lines = append(lines, "")
 	}
 
-	return lines, nil
+	return lines
 }
@@ -6,105 +6,99 @@ import (
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
-type ParamInfo struct {
-	TsType         string
-	Required       bool
-	Included       bool
-	ResolvedParams []*openapi31.Parameter
-}
+// Returns nil if the operation has no parameters
+func (g *generator) buildParams(op *openapi31.Operation, method, path string) (*Params, error) {
+	if len(op.Parameters) == 0 {
+		return nil, nil
+	}
 
-func getParamInfo(reflector *openapi31.Reflector, op *openapi31.Operation, method, path string) (*ParamInfo, error) {
-	paramType := getRequestParamTypeName(method, path)
-	paramRequired := false
-	paramIncluded := len(op.Parameters) > 0
 	resolvedParams := []*openapi31.Parameter{}
-
-	for _, param := range op.Parameters {
+	resolvedParamPointers := []string{}
+	for i, param := range op.Parameters {
+		paramPointer := getOperationPointer(method, path) + jsonPointer("parameters", fmt.Sprint(i))
 		if param.Reference != nil {
-			refParam, err := resolveRefParameter(param.Reference.Ref, reflector)
+			refParam, refPointer, err := resolveRefParameter(param.Reference.Ref, g.reflector)
 			if err != nil {
-				return nil, err
+				return nil, wrapSpecError(err, paramPointer)
 			}
 
 			resolvedParams = append(resolvedParams, refParam)
+			resolvedParamPointers = append(resolvedParamPointers, refPointer)
 		} else if param.Parameter != nil {
 			resolvedParams = append(resolvedParams, param.Parameter)
+			resolvedParamPointers = append(resolvedParamPointers, paramPointer)
 		} else {
-			return nil, fmt.Errorf("parameter is nil")
+			return nil, wrapSpecError(specErrorf("parameter is nil"), paramPointer)
 		}
 	}
 
-	// Check if the param is required
-	for _, param := range resolvedParams {
-		if param.Required != nil && *param.Required {
-			paramRequired = true
-			break
+	params := &Params{TypeName: g.requestParamTypeName(method, path)}
+	errs := SpecErrors{}
+
+	// Keep the locations in the order they first appear in, so the output is stable
+	groups := map[openapi31.ParameterIn]*ParamGroup{}
+	for i, param := range resolvedParams {
+		group, ok := groups[param.In]
+		if !ok {
+			group = &ParamGroup{In: string(param.In)}
+			groups[param.In] = group
+			params.Groups = append(params.Groups, group)
 		}
-	}
 
-	return &ParamInfo{
-		TsType:         paramType,
-		Required:       paramRequired,
-		Included:       paramIncluded,
-		ResolvedParams: resolvedParams,
-	}, nil
-}
+		schemaPointer := resolvedParamPointers[i] + jsonPointer("schema")
+		g.owner = hoistOwner{name: params.TypeName + toIdentifier(param.Name, CasingPascal), pointer: schemaPointer, hoistRoot: true}
+		paramType, err := g.schemaToType(param.Schema, schemaPointer)
+		if err != nil {
+			errs = errs.append(err)
+			continue
+		}
 
-func generateParamType(method, path string, paramInfo *ParamInfo) ([]string, error) {
-	lines := []string{}
+		paramRequired := param.Required != nil && *param.Required
+		description := ""
+		if param.Description != nil {
+			description = *param.Description
+		}
 
-	if !paramInfo.Included {
-		return lines, nil
+		group.Params = append(group.Params, &Param{
+			Name:        param.Name,
+			Required:    paramRequired,
+			Description: description,
+			Pointer:     resolvedParamPointers[i],
+			Type:        paramType,
+		})
+
+		if paramRequired {
+			group.Required = true
+			params.Required = true
+		}
 	}
 
-	// Generate the param type
-	lines = append(lines, fmt.Sprintf("type %s = {", getRequestParamTypeName(method, path)))
-
-	paramInMap := map[openapi31.ParameterIn][]*openapi31.Parameter{}
-	for _, param := range paramInfo.ResolvedParams {
-		paramInMap[param.In] = append(paramInMap[param.In], param)
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
-	for in, params := range paramInMap {
-		inRequired := false
-		inLines := []string{}
-		for _, param := range params {
-			paramRequired := param.Required != nil && *param.Required
-			paramType, err := jsonTypeToTypescriptType(param.Schema)
-			if err != nil {
-				return nil, err
-			}
-
-			paramRequiredQ := ""
-			if !paramRequired {
-				paramRequiredQ = "?"
-			}
-
-			if param.Description != nil {
-				docString := buildDocString(*param.Description, "")
-				if docString != "" {
-					inLines = append(inLines, "        "+docString)
-				}
-			}
-
-			inLines = append(inLines, fmt.Sprintf("        %s%s: %s;", param.Name, paramRequiredQ, paramType))
+	return params, nil
+}
 
-			if paramRequired {
-				inRequired = true
-			}
-		}
+func (p *tsPrinter) printParamType(params *Params) []string {
+	if params == nil {
+		return []string{}
+	}
 
-		inRequiredQ := ""
-		if !inRequired {
-			inRequiredQ = "?"
+	paramType := &tsObject{}
+	for _, group := range params.Groups {
+		groupType := &tsObject{}
+		for _, param := range group.Params {
+			groupType.members = append(groupType.members, &tsMember{
+				doc:      buildDocString(param.Description, ""),
+				name:     param.Name,
+				optional: !param.Required,
+				typ:      toTsType(param.Type),
+			})
 		}
 
-		lines = append(lines, fmt.Sprintf("    %s%s: {", in, inRequiredQ))
-		lines = append(lines, inLines...)
-		lines = append(lines, "    };")
+		paramType.members = append(paramType.members, &tsMember{name: group.In, optional: !group.Required, typ: groupType})
 	}
 
-	lines = append(lines, "}")
-
-	return lines, nil
+	return p.typeAlias("", params.TypeName, paramType)
 }
@@ -0,0 +1,81 @@
+package typedfetch
+
+import (
+	"fmt"
+)
+
+// The TypeScript of a type expression of the model
+func toTsType(t Type) tsType {
+	switch t := t.(type) {
+	case *KeywordType:
+		if t.Comment != "" {
+			return &tsCommented{comment: t.Comment, typ: &tsKeyword{t.Keyword}}
+		}
+		return &tsKeyword{t.Keyword}
+	case *ReferenceType:
+		return &tsReference{name: t.Name, args: toTsTypes(t.TypeArguments)}
+	case *LiteralType:
+		if s, ok := t.Value.(string); ok {
+			return &tsStringLiteral{s}
+		}
+		return &tsLiteral{fmt.Sprint(t.Value)}
+	case *ArrayType:
+		return &tsArray{toTsType(t.Items)}
+	case *ObjectType:
+		object := &tsObject{}
+		for _, property := range t.Properties {
+			object.members = append(object.members, &tsMember{
+				doc:      buildDocString(property.Description, property.Example),
+				name:     property.Name,
+				optional: property.Optional,
+				typ:      toTsType(property.Type),
+			})
+		}
+		for _, signature := range t.PrefixSignatures {
+			object.members = append(object.members, &tsMember{
+				index: true,
+				name:  "key",
+				key:   &tsTemplateLiteral{prefix: signature.Prefix},
+				typ:   toTsType(signature.Type),
+			})
+		}
+		if t.IndexSignature != nil {
+			object.members = append(object.members, &tsMember{index: true, name: "key", typ: toTsType(t.IndexSignature)})
+		}
+		return object
+	case *UnionType:
+		return &tsUnion{toTsTypes(t.Members)}
+	case *IntersectionType:
+		return &tsIntersection{toTsTypes(t.Members)}
+	case *EnumType:
+		union := &tsUnion{}
+		for _, member := range t.Members {
+			union.members = append(union.members, toTsType(&LiteralType{Value: member.Value}))
+		}
+		return union
+	case *RawType:
+		return &tsRaw{t.TypeScript}
+	}
+
+	panic(fmt.Sprintf("unknown type %T", t))
+}
+
+func toTsTypes(types []Type) []tsType {
+	converted := []tsType{}
+	for _, t := range types {
+		converted = append(converted, toTsType(t))
+	}
+	return converted
+}
+
+func tsReferenceTo(name string, args ...tsType) *tsReference {
+	return &tsReference{name: name, args: args}
+}
+
+func tsStringLiterals(values ...string) *tsUnion {
+	union := &tsUnion{}
+	for _, value := range values {
+		union.members = append(union.members, &tsStringLiteral{value})
+	}
+	return union
+}
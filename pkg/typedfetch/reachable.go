@@ -0,0 +1,114 @@
+package typedfetch
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Names of the component schemas referenced by the selected operations, directly or through any chain of $refs
+// (other schemas, parameters, request bodies, responses, headers, and discriminator mappings)
+func (g *generator) reachableComponentSchemas() (map[string]bool, error) {
+	specJson, err := json.Marshal(g.reflector.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	err = json.Unmarshal(specJson, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []any{}
+	paths, _ := doc["paths"].(map[string]any)
+	for _, operation := range g.selectedOperations() {
+		pathItem, _ := paths[operation.Path].(map[string]any)
+		pending = append(pending, pathItem[strings.ToLower(operation.Method)])
+	}
+
+	visited := map[string]bool{}
+	reachable := map[string]bool{}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		for _, ref := range collectRefs(node, nil) {
+			if !strings.HasPrefix(ref, "#/") {
+				continue
+			}
+
+			// The whole component is emitted even if the reference points inside of it
+			tokens := refTokens(ref)
+			if len(tokens) >= 3 && tokens[0] == "components" && tokens[1] == "schemas" {
+				reachable[tokens[2]] = true
+				tokens = tokens[:3]
+			}
+
+			key := strings.Join(tokens, "/")
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			target, ok := resolveTokens(doc, tokens)
+			if ok {
+				pending = append(pending, target)
+			}
+		}
+	}
+
+	return reachable, nil
+}
+
+// Every $ref (and discriminator mapping) under node
+func collectRefs(node any, refs []string) []string {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			refs = append(refs, ref)
+		}
+
+		if discriminator, ok := v["discriminator"].(map[string]any); ok {
+			mapping, _ := discriminator["mapping"].(map[string]any)
+			for _, key := range sortedMapKeys(mapping) {
+				if ref, ok := mapping[key].(string); ok {
+					refs = append(refs, ref)
+				}
+			}
+		}
+
+		for _, key := range sortedMapKeys(v) {
+			refs = collectRefs(v[key], refs)
+		}
+	case []any:
+		for _, item := range v {
+			refs = collectRefs(item, refs)
+		}
+	}
+	return refs
+}
+
+// i.e. #/components/schemas/a~1b -> [components schemas a/b]
+func refTokens(ref string) []string {
+	tokens := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+	for i, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return tokens
+}
+
+func resolveTokens(node any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		node, ok = m[token]
+		if !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
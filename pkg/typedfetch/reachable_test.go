@@ -0,0 +1,96 @@
+package typedfetch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReachableComponentSchemas(t *testing.T) {
+	// An operation responding with the given schema
+	responding := func(schema string) string {
+		return "{/pets: {get: {responses: {'200': {description: ok, content: {application/json: {schema: " + schema + "}}}}}}}"
+	}
+
+	tests := []struct {
+		name    string
+		schemas string
+		paths   string
+		options Options
+
+		// Names of the generated components, in order
+		want []string
+	}{
+		{
+			name:    "unreferenced",
+			schemas: "{A: {type: string}, B: {type: string}}",
+			paths:   responding("{type: string}"),
+			want:    []string{},
+		},
+		{
+			name: "transitive",
+			schemas: "{A: {type: object, properties: {b: {$ref: '#/components/schemas/B'}}}, " +
+				"B: {type: array, items: {$ref: '#/components/schemas/C'}}, C: {type: string}, D: {type: string}}",
+			paths: responding("{$ref: '#/components/schemas/A'}"),
+			want:  []string{"A", "B", "C"},
+		},
+		{
+			name: "cycle",
+			schemas: "{A: {type: object, properties: {b: {$ref: '#/components/schemas/B'}}}, " +
+				"B: {type: object, properties: {a: {$ref: '#/components/schemas/A'}, self: {$ref: '#/components/schemas/B'}}}, C: {type: string}}",
+			paths: responding("{$ref: '#/components/schemas/B'}"),
+			want:  []string{"A", "B"},
+		},
+		{
+			name: "discriminator mapping only",
+			schemas: "{Pet: {type: object, properties: {kind: {type: string}}, discriminator: {propertyName: kind, mapping: {dog: '#/components/schemas/Dog'}}}, " +
+				"Dog: {type: object, properties: {bark: {type: boolean}}}, Cat: {type: object}}",
+			paths: responding("{$ref: '#/components/schemas/Pet'}"),
+			want:  []string{"Dog", "Pet"},
+		},
+		{
+			name: "allOf only",
+			schemas: "{Animal: {type: object, properties: {name: {type: string}}}, " +
+				"Dog: {allOf: [{$ref: '#/components/schemas/Animal'}, {type: object, properties: {bark: {type: boolean}}}]}, Cat: {type: object}}",
+			paths: responding("{$ref: '#/components/schemas/Dog'}"),
+			want:  []string{"Animal", "Dog"},
+		},
+		{
+			name: "reference into a component",
+			schemas: "{A: {type: object, properties: {b: {$ref: '#/components/schemas/B'}, c: {type: string}}}, " +
+				"B: {type: string}, C: {type: string}}",
+			paths: responding("{$ref: '#/components/schemas/A/properties/c'}"),
+			want:  []string{"A", "B"},
+		},
+		{
+			name:    "parameters and request bodies",
+			schemas: "{A: {type: string}, B: {type: object, properties: {id: {type: integer}}}, C: {type: string}}",
+			paths: "{/pets: {post: {parameters: [{name: a, in: query, schema: {$ref: '#/components/schemas/A'}}], " +
+				"requestBody: {content: {application/json: {schema: {$ref: '#/components/schemas/B'}}}}, responses: {'204': {description: ok}}}}}",
+			want: []string{"A", "B"},
+		},
+		{
+			name:    "filtered out operation",
+			schemas: "{A: {type: string}, B: {type: string}}",
+			paths: "{/a: {get: {tags: [a], responses: {'200': {description: ok, content: {application/json: {schema: {$ref: '#/components/schemas/A'}}}}}}}, " +
+				"/b: {get: {tags: [b], responses: {'200': {description: ok, content: {application/json: {schema: {$ref: '#/components/schemas/B'}}}}}}}}",
+			options: Options{Include: OperationFilter{Tags: []string{"b"}}},
+			want:    []string{"B"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := test.options
+			options.TreeShake = true
+			model := buildTestModel(t, test.schemas, test.paths, options)
+
+			got := []string{}
+			for _, component := range model.Components {
+				got = append(got, component.SchemaName)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("components = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -1,84 +1,29 @@
 package typedfetch
 
-import (
-	"fmt"
-)
-
-func generateRequestTypes(method, path string, paramInfo *ParamInfo, bodyInfo *RequestBodyInfo) ([]string, error) {
-	lines := []string{}
-
-	paramLines, err := generateParamType(method, path, paramInfo)
-	if err != nil {
-		return nil, err
-	}
-	lines = append(lines, paramLines...)
-
-	bodyLines, err := generateBodyType(method, path, bodyInfo)
-	if err != nil {
-		return nil, err
-	}
-	lines = append(lines, bodyLines...)
-
-	// Generate the request type
-	// Example:
-	// type FetchRequestGetFoo = RequestInit & { params?: RequestParamGetFoo; };
-	// type FetchRequestGetFoo2 = RequestInit;
-	// type FetchRequestPostBar = Omit<RequestInit, "body"> & { params: RequestParamPostBar; body: ComponentSchemaAddress; };
-	requestTypeLines, err := generateRequestType(method, path, paramInfo.TsType, paramInfo.Required, paramInfo.Included, bodyInfo.TsType, bodyInfo.Required, bodyInfo.Included)
-	if err != nil {
-		return nil, err
+// Generate the request type
+// Example:
+// type RequestGetFoo = Omit<RequestInit, 'headers'> & { params?: ParamGetFoo; } & RequestInitExtended;
+// type RequestGetFoo2 = Omit<RequestInit, 'headers'> & RequestInitExtended;
+// type RequestPostBar = Omit<RequestInit, 'headers' | 'body'> & { params: ParamPostBar; body: BodyPostBar; } & RequestInitExtended;
+func (p *tsPrinter) printRequestType(op *Operation) []string {
+	omitted := tsStringLiterals("headers", "body")
+	if op.Body == nil {
+		omitted = tsStringLiterals("headers")
 	}
-	lines = append(lines, requestTypeLines...)
 
-	return lines, nil
-}
-
-func generateRequestType(method, path, paramType string, paramRequired, paramIncluded bool, bodyType string, bodyRequired, bodyIncluded bool) ([]string, error) {
-	lines := []string{}
-
-	paramRequiredQ := ""
-	if !paramRequired {
-		paramRequiredQ = "?"
-	}
+	requestType := &tsIntersection{[]tsType{tsReferenceTo("Omit", tsReferenceTo("RequestInit"), omitted)}}
 
-	bodyRequiredQ := ""
-	if !bodyRequired {
-		bodyRequiredQ = "?"
+	init := &tsObject{inline: true}
+	if op.Params != nil {
+		init.members = append(init.members, &tsMember{name: "params", optional: !op.Params.Required, typ: tsReferenceTo(op.Params.TypeName)})
 	}
-
-	paramDecl := ""
-	if paramIncluded {
-		paramDecl = fmt.Sprintf("params%s: %s;", paramRequiredQ, paramType)
+	if op.Body != nil {
+		init.members = append(init.members, &tsMember{name: "body", optional: !op.Body.Required, typ: tsReferenceTo(op.Body.TypeName)})
 	}
-
-	bodyDecl := ""
-	if bodyIncluded {
-		bodyDecl = fmt.Sprintf("body%s: %s;", bodyRequiredQ, bodyType)
+	if len(init.members) > 0 {
+		requestType.members = append(requestType.members, init)
 	}
 
-	baseType := `Omit<RequestInit, 'headers' | 'body'>`
-	if !bodyIncluded {
-		baseType = "Omit<RequestInit, 'headers'>"
-	}
-
-	intersectionType := ""
-	if paramDecl != "" || bodyDecl != "" {
-		intersectionType = fmt.Sprintf(" & { %s %s }", paramDecl, bodyDecl)
-	}
-
-	lines = append(lines, fmt.Sprintf("type %s = %s%s & RequestInitExtended;", getRequestTypeName(method, path), baseType, intersectionType))
-
-	return lines, nil
-}
-
-func getRequestTypeName(method, path string) string {
-	return fmt.Sprintf("Request%s", getUniqueEndpointName(method, path))
-}
-
-func getRequestParamTypeName(method, path string) string {
-	return fmt.Sprintf("Param%s", getUniqueEndpointName(method, path))
-}
-
-func getRequestBodyTypeName(method, path string) string {
-	return fmt.Sprintf("Body%s", getUniqueEndpointName(method, path))
+	requestType.members = append(requestType.members, tsReferenceTo("RequestInitExtended"))
+	return p.typeAlias("", op.RequestTypeName, requestType)
 }
@@ -1,89 +1,76 @@
 package typedfetch
 
 import (
-	"fmt"
-
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
-type RequestBodyInfo struct {
-	TsType       string
-	Required     bool
-	Included     bool
-	ResolvedBody *openapi31.RequestBody
-}
-
-func getRequestBodyInfo(reflector *openapi31.Reflector, op *openapi31.Operation, method, path string) (*RequestBodyInfo, error) {
-	bodyType := getRequestBodyTypeName(method, path)
-	bodyRequired := false
-	bodyIncluded := op.RequestBody != nil
-	var resolvedBody *openapi31.RequestBody
-
-	if bodyIncluded {
-		if op.RequestBody.Reference != nil {
-			body, err := resolveRefRequestBody(op.RequestBody.Reference.Ref, reflector)
-			if err != nil {
-				return nil, err
-			}
-
-			resolvedBody = body
-		} else if op.RequestBody.RequestBody != nil {
-			resolvedBody = op.RequestBody.RequestBody
-		} else {
-			return nil, fmt.Errorf("request body is nil")
-		}
+var preferredContentTypes = []string{"application/json", "multipart/form-data", "application/x-www-form-urlencoded", "application/octet-stream"}
 
-		if resolvedBody.Required != nil && *resolvedBody.Required {
-			bodyRequired = true
-		}
+// Returns nil if the operation has no request body
+func (g *generator) buildBody(op *openapi31.Operation, method, path string) (*Body, error) {
+	if op.RequestBody == nil {
+		return nil, nil
 	}
 
-	return &RequestBodyInfo{
-		TsType:       bodyType,
-		Required:     bodyRequired,
-		Included:     bodyIncluded,
-		ResolvedBody: resolvedBody,
-	}, nil
-}
-
-func generateBodyType(method, path string, bodyInfo *RequestBodyInfo) ([]string, error) {
-	lines := []string{}
-
-	if !bodyInfo.Included {
-		return lines, nil
-	}
+	var resolvedBody *openapi31.RequestBody
+	bodyPointer := getOperationPointer(method, path) + jsonPointer("requestBody")
 
-	preferredContentTypes := []string{"application/json", "multipart/form-data", "application/x-www-form-urlencoded", "application/octet-stream"}
-	contentType := ""
-	for _, preferredContentType := range preferredContentTypes {
-		if _, ok := bodyInfo.ResolvedBody.Content[preferredContentType]; ok {
-			contentType = preferredContentType
-			break
+	if op.RequestBody.Reference != nil {
+		body, refPointer, err := resolveRefRequestBody(op.RequestBody.Reference.Ref, g.reflector)
+		if err != nil {
+			return nil, wrapSpecError(err, bodyPointer)
 		}
-	}
 
-	if contentType == "" {
-		// default to the first content type
-		for contentType = range bodyInfo.ResolvedBody.Content {
-			break
-		}
+		resolvedBody = body
+		bodyPointer = refPointer
+	} else if op.RequestBody.RequestBody != nil {
+		resolvedBody = op.RequestBody.RequestBody
+	} else {
+		return nil, wrapSpecError(specErrorf("request body is nil"), bodyPointer)
 	}
 
+	contentType := selectContentType(resolvedBody.Content)
 	if contentType == "" {
-		return nil, fmt.Errorf("no content type found for request body")
+		return nil, wrapSpecError(specErrorf("no content type found for request body"), bodyPointer+jsonPointer("content"))
 	}
 
 	// TODO: register the content type in map?
 
-	// Generate the body type
-	bodyType, err := jsonTypeToTypescriptType(bodyInfo.ResolvedBody.Content[contentType].Schema)
+	typeName := g.requestBodyTypeName(method, path)
+	schemaPointer := bodyPointer + jsonPointer("content", contentType, "schema")
+	g.owner = hoistOwner{name: typeName, pointer: schemaPointer}
+	bodyType, err := g.schemaToType(resolvedBody.Content[contentType].Schema, schemaPointer)
 	if err != nil {
 		return nil, err
 	}
 
-	bodyDecl := fmt.Sprintf("type %s = %s;", getRequestBodyTypeName(method, path), bodyType)
+	return &Body{
+		TypeName:    typeName,
+		Required:    resolvedBody.Required != nil && *resolvedBody.Required,
+		ContentType: contentType,
+		Pointer:     bodyPointer,
+		Type:        bodyType,
+	}, nil
+}
+
+// The first preferred content type, or else the first in alphabetical order; empty if there's no content
+func selectContentType(content map[string]openapi31.MediaType) string {
+	for _, preferredContentType := range preferredContentTypes {
+		if _, ok := content[preferredContentType]; ok {
+			return preferredContentType
+		}
+	}
+
+	for _, contentType := range sortedMapKeys(content) {
+		return contentType
+	}
+	return ""
+}
 
-	lines = append(lines, bodyDecl)
+func (p *tsPrinter) printBodyType(body *Body) []string {
+	if body == nil {
+		return []string{}
+	}
 
-	return lines, nil
+	return p.printNamedType("", body.TypeName, body.Type)
 }
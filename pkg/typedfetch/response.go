@@ -1,110 +1,106 @@
 package typedfetch
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/swaggest/openapi-go/openapi31"
 )
 
-func generateResponseTypes(reflector *openapi31.Reflector, op *openapi31.Operation, method, path string) ([]string, error) {
-	lines := []string{}
+// Data = first non-error response or default, error = first error response or default
+func (g *generator) buildResponses(op *openapi31.Operation, method, path string) (*Response, *Response, error) {
+	reflector := g.reflector
+	errs := SpecErrors{}
 
-	// Data = first non-error response or default
-	dataInfo, err := getResponseObj(reflector, op, method, path, []string{"2"})
+	dataInfo, dataPointer, err := getResponseObj(reflector, op, method, path, []string{"2"})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	dataResponseTypeName := getResponseDataTypeName(method, path)
-	dataResponseLines, err := generateResponseType(method, path, dataResponseTypeName, dataInfo)
+	responseData, err := g.buildResponse(g.responseDataTypeName(method, path), dataInfo, dataPointer)
 	if err != nil {
-		return nil, err
+		errs = errs.append(err)
 	}
-	lines = append(lines, dataResponseLines...)
 
-	// Error = first error response or default
-	errInfo, err := getResponseObj(reflector, op, method, path, []string{"4", "5"})
+	errInfo, errPointer, err := getResponseObj(reflector, op, method, path, []string{"4", "5"})
 	if err != nil {
-		return nil, err
+		return nil, nil, errs.append(err)
 	}
 
-	errResponseTypeName := getResponseErrTypeName(method, path)
-	errResponseLines, err := generateResponseType(method, path, errResponseTypeName, errInfo)
+	responseError, err := g.buildResponse(g.responseErrTypeName(method, path), errInfo, errPointer)
 	if err != nil {
-		return nil, err
+		errs = errs.append(err)
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errs
 	}
-	lines = append(lines, errResponseLines...)
 
-	return lines, nil
+	return responseData, responseError, nil
 }
 
-func generateResponseType(method, path, typeName string, response *openapi31.Response) ([]string, error) {
-	lines := []string{}
+func (g *generator) buildResponse(typeName string, response *openapi31.Response, responsePointer string) (*Response, error) {
+	built := &Response{
+		TypeName:    typeName,
+		ContentType: selectContentType(response.Content),
+		Pointer:     responsePointer,
+	}
 
-	preferredContentTypes := []string{"application/json", "multipart/form-data", "application/x-www-form-urlencoded", "application/octet-stream"}
-	contentType := ""
-	for _, preferredContentType := range preferredContentTypes {
-		if _, ok := response.Content[preferredContentType]; ok {
-			contentType = preferredContentType
-			break
-		}
+	// Empty response
+	if built.ContentType == "" {
+		return built, nil
 	}
 
-	if contentType == "" {
-		// default to the first content type
-		for contentType = range response.Content {
-			break
-		}
+	schemaPointer := responsePointer + jsonPointer("content", built.ContentType, "schema")
+	g.owner = hoistOwner{name: typeName, pointer: schemaPointer}
+	responseType, err := g.schemaToType(response.Content[built.ContentType].Schema, schemaPointer)
+	if err != nil {
+		return nil, err
 	}
 
-	var responseType string
+	built.Type = responseType
+	return built, nil
+}
 
-	if contentType == "" {
-		// Empty response
-		responseType = "{}"
-	} else {
-		// Generate the body type
-		var err error
-		responseType, err = jsonTypeToTypescriptType(response.Content[contentType].Schema)
-		if err != nil {
-			return nil, fmt.Errorf("%s %s, %s (%s): %v", method, path, typeName, contentType, err)
-		}
+func (p *tsPrinter) printResponseType(response *Response) []string {
+	if response.Type == nil {
+		return p.typeAlias("", response.TypeName, &tsObject{})
 	}
 
-	responseDecl := fmt.Sprintf("type %s = %s;", typeName, responseType)
-	lines = append(lines, responseDecl)
-
-	return lines, nil
+	return p.printNamedType("", response.TypeName, response.Type)
 }
 
-func getResponseObj(reflector *openapi31.Reflector, op *openapi31.Operation, method, path string, codePrefixes []string) (*openapi31.Response, error) {
+// Returns the response along with its JSON Pointer in the document
+func getResponseObj(reflector *openapi31.Reflector, op *openapi31.Operation, method, path string, codePrefixes []string) (*openapi31.Response, string, error) {
+	responsesPointer := getOperationPointer(method, path) + jsonPointer("responses")
 	if op.Responses == nil {
-		return nil, fmt.Errorf("operation %s %s has no responses", method, path)
+		return nil, "", wrapSpecError(specErrorf("operation %s %s has no responses", method, path), responsesPointer)
 	}
 
 	// Find the first matching response
 	for _, codePrefix := range codePrefixes {
-		for code, responseOrRef := range op.Responses.MapOfResponseOrReferenceValues {
+		for _, code := range sortedMapKeys(op.Responses.MapOfResponseOrReferenceValues) {
+			responseOrRef := op.Responses.MapOfResponseOrReferenceValues[code]
 			if strings.HasPrefix(code, codePrefix) {
-				resolvedResponse, err := resolveResponseOrReference(reflector, &responseOrRef)
+				responsePointer := responsesPointer + jsonPointer(code)
+				resolvedResponse, resolvedPointer, err := resolveResponseOrReference(reflector, &responseOrRef, responsePointer)
 				if err != nil {
-					return nil, err
+					return nil, "", wrapSpecError(err, responsePointer)
 				}
 
-				return resolvedResponse, nil
+				return resolvedResponse, resolvedPointer, nil
 			}
 		}
 	}
 
 	// If no matching response was found, use the default response
 	if op.Responses.Default != nil {
-		resolvedResponse, err := resolveResponseOrReference(reflector, op.Responses.Default)
+		responsePointer := responsesPointer + jsonPointer("default")
+		resolvedResponse, resolvedPointer, err := resolveResponseOrReference(reflector, op.Responses.Default, responsePointer)
 		if err != nil {
-			return nil, err
+			return nil, "", wrapSpecError(err, responsePointer)
 		}
 
-		return resolvedResponse, nil
+		return resolvedResponse, resolvedPointer, nil
 	}
 
 	// No matching response or default response found, return an empty response
@@ -112,39 +108,32 @@ func getResponseObj(reflector *openapi31.Reflector, op *openapi31.Operation, met
 		Content: map[string]openapi31.MediaType{},
 	}
 
-	return response, nil
+	return response, responsesPointer, nil
 }
 
-func resolveResponseOrReference(reflector *openapi31.Reflector, responseOrRef *openapi31.ResponseOrReference) (*openapi31.Response, error) {
+func resolveResponseOrReference(reflector *openapi31.Reflector, responseOrRef *openapi31.ResponseOrReference, pointer string) (*openapi31.Response, string, error) {
 	if responseOrRef.Reference != nil {
 		return resolveRefResponse(responseOrRef.Reference.Ref, reflector)
 	}
 
-	return responseOrRef.Response, nil
+	return responseOrRef.Response, pointer, nil
 }
 
-func resolveRefResponse(ref string, reflector *openapi31.Reflector) (*openapi31.Response, error) {
+// Returns the response along with its JSON Pointer in the document
+func resolveRefResponse(ref string, reflector *openapi31.Reflector) (*openapi31.Response, string, error) {
 	if !strings.HasPrefix(ref, "#/components/responses/") {
-		return nil, fmt.Errorf("reference %s is not a response", ref)
+		return nil, "", specErrorf("reference %s is not a response", ref)
 	}
 
 	responseName := strings.TrimPrefix(ref, "#/components/responses/")
-	responseOrReference, ok := reflector.Spec.Components.Responses[responseName]
+	responseOrReference, ok := specComponents(reflector).Responses[responseName]
 	if !ok {
-		return nil, fmt.Errorf("response %s not found", responseName)
+		return nil, "", specErrorf("response %s not found", responseName)
 	}
 
 	if responseOrReference.Reference != nil {
 		return resolveRefResponse(responseOrReference.Reference.Ref, reflector)
 	}
 
-	return responseOrReference.Response, nil
-}
-
-func getResponseDataTypeName(method, path string) string {
-	return fmt.Sprintf("ResponseData%s", getUniqueEndpointName(method, path))
-}
-
-func getResponseErrTypeName(method, path string) string {
-	return fmt.Sprintf("ResponseError%s", getUniqueEndpointName(method, path))
+	return responseOrReference.Response, refToPointer(ref), nil
 }
@@ -5,152 +5,349 @@ import (
 	"strings"
 )
 
-func jsonTypeToTypescriptType(schema map[string]any) (string, error) {
+// Translate the schema at pointer (used for error reporting) to a type expression.
+// In lenient mode, any schema that can't be translated becomes unknown instead of an error.
+func (g *generator) schemaToType(schema map[string]any, pointer string) (Type, error) {
+	t, err := g.translateSchema(schema, pointer)
+	if err != nil && g.options.Lenient {
+		return g.degradeToUnknown(err), nil
+	}
+
+	return t, err
+}
+
+func (g *generator) translateSchema(schema map[string]any, pointer string) (Type, error) {
+	override, err := g.overrideType(schema, pointer)
+	if override != nil || err != nil {
+		return override, err
+	}
+
+	// Escape hatch for types that can't be described by the schema
+	if tsType, ok := schema["x-typescript-type"]; ok {
+		tsTypeString, ok := tsType.(string)
+		if !ok || tsTypeString == "" {
+			return nil, specErrorAt(pointer+jsonPointer("x-typescript-type"), "expected a TypeScript type: %v", tsType)
+		}
+		return &RawType{TypeScript: tsTypeString}, nil
+	}
+
 	ref, ok := schema["$ref"].(string)
 	if ok {
 		if !strings.HasPrefix(ref, "#/components/schemas/") {
-			return "", fmt.Errorf("unsupported ref, expected #/components/schemas/: %v", ref)
+			return nil, specErrorAt(pointer, "unsupported ref, expected #/components/schemas/: %v", ref)
 		}
 
-		componentName := getComponentSchemaTypeName(strings.TrimPrefix(ref, "#/components/schemas/"))
-		return componentName, nil
+		componentName := g.componentSchemaTypeName(strings.TrimPrefix(ref, "#/components/schemas/"))
+		return &ReferenceType{Name: componentName, Ref: ref}, nil
 	}
 
+	schema = g.withoutUnsupportedKeywords(schema, pointer)
+
 	// if empty schema, it's an any type
 	if len(schema) == 0 {
-		return "any", nil
+		return &KeywordType{Keyword: "any"}, nil
+	}
+
+	if isComposedSchema(schema) {
+		return g.composedSchemaToType(schema, pointer)
+	}
+
+	// OpenAPI 3.1 allows a list of types, i.e. type: [string, "null"]
+	if componentTypes, ok := schema["type"].([]any); ok {
+		return g.typeListToType(schema, componentTypes, pointer)
 	}
 
 	componentType, ok := schema["type"].(string)
 	if !ok || !isValidJsonType(componentType) {
-		return "", fmt.Errorf("invalid type: %v", componentType)
+		return nil, specErrorAt(pointer, "invalid type: %v", componentType)
 	}
 
 	switch componentType {
 	case "object":
-		return jsonObjectToTypescriptType(schema)
+		t, err := g.objectSchemaToType(schema, pointer)
+		if _, ok := t.(*ReferenceType); err == nil && !ok && g.options.HoistInlineTypes && g.isInline(pointer) {
+			return g.hoist(t, pointer), nil
+		}
+		return t, err
 	case "array":
-		return jsonArrayToTypescriptType(schema)
+		return g.arraySchemaToType(schema, pointer)
 	case "string":
-		return jsonStringToTypescriptType(schema)
+		return g.stringSchemaToType(schema, pointer)
 	case "number", "integer":
-		return "number", nil
+		if enum, ok := schema["enum"].([]any); ok {
+			return g.enumType(schema, enum, "number", pointer)
+		}
+		return g.numberFormatType(getStringProp(schema, "format")), nil
 	case "boolean":
-		return "boolean", nil
+		return &KeywordType{Keyword: "boolean"}, nil
+	case "null":
+		return &KeywordType{Keyword: "null"}, nil
 	}
 
-	return "", fmt.Errorf("unsupported type: %v", componentType)
+	return nil, specErrorAt(pointer, "unsupported type: %v", componentType)
+}
+
+// Warn about every error that caused a schema to be degraded, and explain it in the generated type
+func (g *generator) degradeToUnknown(err error) Type {
+	reasons := []string{}
+	for _, specErr := range (SpecErrors{}).append(err) {
+		g.warn(specErr)
+		reasons = append(reasons, specErr.Message)
+	}
+
+	return &KeywordType{Keyword: "unknown", Comment: "typed-fetch: " + strings.Join(reasons, "; ")}
+}
+
+var compositionKeywords = []string{"allOf", "anyOf", "oneOf"}
+
+func isComposedSchema(schema map[string]any) bool {
+	for _, keyword := range compositionKeywords {
+		if _, ok := schema[keyword]; ok {
+			return true
+		}
+	}
+	return false
 }
 
-func jsonObjectToTypescriptType(schema map[string]any) (string, error) {
+// allOf -> A & B, anyOf/oneOf -> A | B
+// Any sibling type (i.e. type: object alongside allOf) is intersected with the composition
+func (g *generator) composedSchemaToType(schema map[string]any, pointer string) (Type, error) {
+	parts := []Type{}
+
+	if _, ok := schema["type"]; ok {
+		baseSchema := copySchema(schema)
+		for _, keyword := range compositionKeywords {
+			delete(baseSchema, keyword)
+		}
+
+		baseType, err := g.schemaToType(baseSchema, pointer)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, baseType)
+	}
+
+	errs := SpecErrors{}
+	for _, keyword := range compositionKeywords {
+		subschemas, ok := schema[keyword].([]any)
+		if !ok {
+			continue
+		}
+
+		memberTypes := []Type{}
+		for i, subschema := range subschemas {
+			memberPointer := pointer + jsonPointer(keyword, fmt.Sprint(i))
+			memberSchema, ok := subschema.(map[string]any)
+			if !ok {
+				errs = errs.append(specErrorAt(memberPointer, "invalid schema: %v", subschema))
+				continue
+			}
+
+			memberType, err := g.schemaToType(memberSchema, memberPointer)
+			if err != nil {
+				errs = errs.append(err)
+				continue
+			}
+			memberTypes = append(memberTypes, memberType)
+		}
+
+		if len(subschemas) == 0 {
+			errs = errs.append(specErrorAt(pointer+jsonPointer(keyword), "expected at least one schema"))
+			continue
+		}
+
+		if keyword == "allOf" {
+			parts = append(parts, &IntersectionType{Members: memberTypes})
+		} else {
+			parts = append(parts, &UnionType{Members: memberTypes})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return &IntersectionType{Members: parts}, nil
+}
+
+func (g *generator) typeListToType(schema map[string]any, componentTypes []any, pointer string) (Type, error) {
+	memberTypes := []Type{}
+	for _, componentType := range componentTypes {
+		typeName, ok := componentType.(string)
+		if !ok || !isValidJsonType(typeName) {
+			return nil, specErrorAt(pointer, "invalid type: %v", componentType)
+		}
+
+		memberSchema := copySchema(schema)
+		memberSchema["type"] = typeName
+		if typeName != "object" {
+			for _, keyword := range conditionalKeywords {
+				delete(memberSchema, keyword)
+			}
+		}
+		memberType, err := g.translateSchema(memberSchema, pointer)
+		if err != nil {
+			return nil, err
+		}
+		memberTypes = append(memberTypes, memberType)
+	}
+
+	if len(memberTypes) == 0 {
+		return nil, specErrorAt(pointer, "invalid type: %v", componentTypes)
+	}
+
+	return &UnionType{Members: memberTypes}, nil
+}
+
+func (g *generator) objectSchemaToType(schema map[string]any, pointer string) (Type, error) {
 	properties, ok := schema["properties"].(map[string]any)
 	if !ok {
 		properties = map[string]any{}
 	}
 
-	additionalProperties, ok := schema["additionalProperties"]
-	hasAdditionalProps := ok
+	additionalProperties, hasAdditionalProps := schema["additionalProperties"]
+	_, hasPatternProps := schema["patternProperties"]
+	_, hasPropertyNames := schema["propertyNames"]
 
-	if len(properties) == 0 && !hasAdditionalProps {
-		return "", fmt.Errorf("missing properties or additionalProperties: %v", schema["properties"])
+	if len(properties) == 0 && !hasAdditionalProps && !hasPatternProps && !hasPropertyNames {
+		// An object without properties accepts any properties
+		if g.options.Lenient {
+			return &ReferenceType{Name: "Record", TypeArguments: []Type{&KeywordType{Keyword: "string"}, &KeywordType{Keyword: "unknown"}}}, nil
+		}
+		return nil, specErrorAt(pointer, "missing properties or additionalProperties: %v", schema["properties"])
 	}
 
 	requiredProps, err := getRequiredProps(schema)
 	if err != nil {
-		return "", err
+		return nil, wrapSpecError(err, pointer)
 	}
 
-	lines := []string{"{"}
+	object := &ObjectType{}
+	errs := SpecErrors{}
 	sortedProperties := sortedMapKeys(properties)
 	for _, property := range sortedProperties {
 		propItem := properties[property]
-		optional := "?"
-		if itemInSlice(requiredProps, property) {
-			optional = ""
-		}
+		propPointer := pointer + jsonPointer("properties", property)
 
 		propSchema, ok := propItem.(map[string]any)
 		if !ok {
-			return "", fmt.Errorf("invalid property schema: %v", propItem)
+			errs = errs.append(specErrorAt(propPointer, "invalid property schema: %v", propItem))
+			continue
 		}
 
-		propType, err := jsonTypeToTypescriptType(propSchema)
+		// Keep going so that every invalid property is reported, not just the first
+		propType, err := g.schemaToType(propSchema, propPointer)
 		if err != nil {
-			return "", fmt.Errorf("%s: %v", property, err)
+			errs = errs.append(err)
+			continue
 		}
 
-		docString := getDocString(propSchema)
-		if docString != "" {
-			lines = append(lines, fmt.Sprintf("    %s", docString))
-		}
-		lines = append(lines, fmt.Sprintf("    %s%s: %s;", property, optional, propType))
+		object.Properties = append(object.Properties, &Property{
+			Name:        property,
+			Optional:    !itemInSlice(requiredProps, property),
+			Description: getDescription(propSchema),
+			Example:     getExample(propSchema),
+			Type:        propType,
+		})
 	}
 
 	// https://swagger.io/docs/specification/data-models/dictionaries/
 	if hasAdditionalProps {
+		additionalPropsPointer := pointer + jsonPointer("additionalProperties")
 		anyAdditionalProps, ok := additionalProperties.(bool)     // additionalProperties: true
 		anyAdditionalProps2, ok2 := additionalProperties.(string) // additionalProperties: {} or additionalProperties: ""
 		if (ok && anyAdditionalProps) || (ok2 && anyAdditionalProps2 == "") {
-			lines = append(lines, "    [key: string]: any;")
+			object.IndexSignature = &KeywordType{Keyword: "any"}
+		} else if ok {
+			// additionalProperties: false
+			object.Closed = true
 		} else {
 			additionalPropertiesSchema, ok := additionalProperties.(map[string]any)
 			if !ok {
-				return "", fmt.Errorf("invalid additionalProperties: %v", schema["additionalProperties"])
+				return nil, errs.append(specErrorAt(additionalPropsPointer, "invalid additionalProperties: %v", schema["additionalProperties"]))
 			}
 
-			propType, err := jsonTypeToTypescriptType(additionalPropertiesSchema)
+			propType, err := g.schemaToType(additionalPropertiesSchema, additionalPropsPointer)
 			if err != nil {
-				return "", err
+				return nil, errs.append(err)
 			}
 
-			lines = append(lines, fmt.Sprintf("    [key: string]: %s;", propType))
+			object.IndexSignature = propType
 		}
 	}
 
-	lines = append(lines, "}")
-	return strings.Join(lines, "\n"), nil
+	err = g.addPatternProperties(object, schema, pointer)
+	if err != nil {
+		errs = errs.append(err)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var record Type
+	if hasPropertyNames {
+		record, err = g.propertyNamesRecord(object, schema, requiredProps, pointer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if record == nil && object.Closed && len(object.Properties) == 0 && len(object.PrefixSignatures) == 0 && object.IndexSignature == nil {
+		// {} would accept anything but null and undefined
+		return &ReferenceType{Name: "Record", TypeArguments: []Type{&KeywordType{Keyword: "string"}, &KeywordType{Keyword: "never"}}}, nil
+	}
+
+	t, err := g.applyConditionals(object, schema, pointer)
+	if err != nil || record == nil {
+		return t, err
+	}
+
+	if t == Type(object) && len(object.Properties) == 0 && len(object.PrefixSignatures) == 0 {
+		return record, nil
+	}
+	return &IntersectionType{Members: []Type{t, record}}, nil
 }
 
-func jsonArrayToTypescriptType(schema map[string]any) (string, error) {
+func (g *generator) arraySchemaToType(schema map[string]any, pointer string) (Type, error) {
 	items, ok := schema["items"].(map[string]any)
 	if !ok {
-		return "", fmt.Errorf("missing items: %v", schema["items"])
+		return nil, specErrorAt(pointer, "missing items: %v", schema["items"])
 	}
 
-	itemType, err := jsonTypeToTypescriptType(items)
+	itemType, err := g.schemaToType(items, pointer+jsonPointer("items"))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("%s[]", itemType), nil
+	return &ArrayType{Items: itemType}, nil
 }
 
-func jsonStringToTypescriptType(schema map[string]any) (string, error) {
+func (g *generator) stringSchemaToType(schema map[string]any, pointer string) (Type, error) {
 	format, ok := schema["format"].(string)
 	if ok {
 		// https://swagger.io/docs/specification/describing-responses/
 		if format == "binary" {
-			var validBinaryFormats = []string{"ArrayBuffer", "Blob", "File"}
-			return strings.Join(validBinaryFormats, " | "), nil
+			return &UnionType{Members: []Type{
+				&ReferenceType{Name: "ArrayBuffer"},
+				&ReferenceType{Name: "Blob"},
+				&ReferenceType{Name: "File"},
+			}}, nil
 		}
 	}
 
 	enum, ok := schema["enum"].([]any)
 	if !ok {
-		return "string", nil
-	}
-
-	enumValues := []string{}
-	for _, value := range enum {
-		valueString, ok := value.(string)
-		if !ok {
-			return "", fmt.Errorf("expected enum value to be a string: %v", value)
+		if formatType := g.stringFormatType(format); formatType != nil {
+			return formatType, nil
 		}
-
-		enumValues = append(enumValues, fmt.Sprintf("'%s'", valueString))
+		return &KeywordType{Keyword: "string"}, nil
 	}
 
-	return strings.Join(enumValues, " | "), nil
+	return g.enumType(schema, enum, "string", pointer)
 }
 
 func getRequiredProps(schema map[string]any) ([]string, error) {
@@ -159,7 +356,7 @@ func getRequiredProps(schema map[string]any) ([]string, error) {
 		for _, prop := range schema["required"].([]any) {
 			propString, ok := prop.(string)
 			if !ok {
-				return nil, fmt.Errorf("expected required property to be a string: %v", prop)
+				return nil, specErrorf("expected required property to be a string: %v", prop)
 			}
 
 			requiredProps = append(requiredProps, propString)
@@ -182,7 +379,19 @@ func getDescription(schema map[string]any) string {
 }
 
 func getExample(schema map[string]any) string {
-	return getStringProp(schema, "example")
+	example := getStringProp(schema, "example")
+	if example != "" {
+		return example
+	}
+
+	// OpenAPI 3.1 schemas use the JSON Schema examples array instead
+	examples, ok := schema["examples"].([]any)
+	if ok && len(examples) > 0 {
+		if str, ok := examples[0].(string); ok {
+			return str
+		}
+	}
+	return ""
 }
 
 func buildDocString(description, example string) string {
@@ -190,6 +399,7 @@ func buildDocString(description, example string) string {
 		return ""
 	}
 
+	description, example = escapeComment(description), escapeComment(example)
 	if description != "" && example != "" {
 		return fmt.Sprintf("/** %s; Example: %s */", description, example)
 	} else if example != "" {
@@ -199,8 +409,7 @@ func buildDocString(description, example string) string {
 	return fmt.Sprintf("/** %s */", description)
 }
 
-func getDocString(schema map[string]any) string {
-	description := getDescription(schema)
-	example := getExample(schema)
-	return buildDocString(description, example)
+// */ would terminate the comment early
+func escapeComment(text string) string {
+	return strings.ReplaceAll(text, "*/", "* /")
 }
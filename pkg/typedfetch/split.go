@@ -0,0 +1,275 @@
+package typedfetch
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const (
+	sharedModule     = "shared"
+	componentsModule = "components"
+	clientModule     = "index"
+
+	// Operations without tags
+	untaggedModule = "untagged"
+)
+
+var (
+	tagModuleRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Like GenerateTypedFetchWithOptions, but split into modules so editors don't have to load one huge file.
+// Returns the contents of each module by file name:
+//
+//	shared.d.ts           types used by every operation
+//	components.d.ts       component types and inline types hoisted to named types
+//	operations/<tag>.d.ts request/response types of the operations whose first tag is <tag> (untagged.d.ts for the rest)
+//	index.d.ts            the client interface (and namespaces if options.Namespaces is set)
+//
+// Every type is exported and modules import the types they use from each other with import type.
+func GenerateTypedFetchFiles(reflector *openapi31.Reflector, options Options) (map[string]string, error) {
+	gen, err := NewGenerator(reflector, options)
+	if err != nil {
+		return nil, err
+	}
+	return gen.GenerateFiles()
+}
+
+// A module of GenerateTypedFetchFiles before its imports are added
+type module struct {
+	lines []string
+
+	// Names of the types it declares
+	declares []string
+
+	// Names of the types it references, and the imports of its RawTypes
+	references map[string]bool
+	imports    map[Import]bool
+}
+
+func newModule(lines []string, declares []string) *module {
+	return &module{lines: lines, declares: declares, references: map[string]bool{}, imports: map[Import]bool{}}
+}
+
+// Record the type names t references, and the imports of its RawTypes
+func (m *module) use(t Type) {
+	switch t := t.(type) {
+	case *ReferenceType:
+		m.references[t.Name] = true
+		for _, arg := range t.TypeArguments {
+			m.use(arg)
+		}
+	case *ArrayType:
+		m.use(t.Items)
+	case *ObjectType:
+		for _, property := range t.Properties {
+			m.use(property.Type)
+		}
+		for _, signature := range t.PrefixSignatures {
+			m.use(signature.Type)
+		}
+		m.use(t.IndexSignature)
+	case *UnionType:
+		for _, member := range t.Members {
+			m.use(member)
+		}
+	case *IntersectionType:
+		for _, member := range t.Members {
+			m.use(member)
+		}
+	case *RawType:
+		for _, i := range t.Imports {
+			m.imports[i] = true
+		}
+	}
+}
+
+// Record a reference to each of the type names
+func (m *module) useNames(names ...string) {
+	for _, name := range names {
+		m.references[name] = true
+	}
+}
+
+func (g *generator) generateFiles() (map[string]string, error) {
+	options := g.options
+	modules := map[string]*module{}
+
+	model, err := g.buildModel()
+	if err != nil {
+		return nil, err
+	}
+
+	p := g.printer.exporting()
+	modules[sharedModule] = newModule(p.printSharedTypes(model.Brands), sharedTypeNames(model.Brands))
+
+	components := newModule(
+		append(p.printComponentTypes(model.Components), p.printInlineTypes(model.InlineTypes)...),
+		append(namedTypeNames(model.Components), namedTypeNames(model.InlineTypes)...),
+	)
+	for _, namedType := range append(append([]*NamedType{}, model.Components...), model.InlineTypes...) {
+		components.use(namedType.Type)
+	}
+	modules[componentsModule] = components
+
+	operationsByTag := map[string][]*Operation{}
+	for _, op := range model.Operations {
+		name := path.Join("operations", untaggedModule)
+		if len(op.Tags) > 0 {
+			name = path.Join("operations", tagModuleName(op.Tags[0]))
+		}
+		operationsByTag[name] = append(operationsByTag[name], op)
+	}
+
+	for _, name := range sortedMapKeys(operationsByTag) {
+		operations := operationsByTag[name]
+		operationTypes := newModule(p.printOperationTypes(operations), operationTypeNames(operations))
+		for _, op := range operations {
+			if op.Failed {
+				continue
+			}
+
+			if op.Params != nil {
+				for _, group := range op.Params.Groups {
+					for _, param := range group.Params {
+						operationTypes.use(param.Type)
+					}
+				}
+			}
+			if op.Body != nil {
+				operationTypes.use(op.Body.Type)
+			}
+			operationTypes.useNames("RequestInitExtended")
+			operationTypes.use(op.ResponseData.Type)
+			operationTypes.use(op.ResponseError.Type)
+		}
+		modules[name] = operationTypes
+	}
+
+	client := newModule(p.printClient(model.Operations), clientTypeNames(model.Operations))
+	for _, op := range model.Operations {
+		if !op.Failed {
+			client.useNames(op.RequestTypeName, op.ResponseData.TypeName, op.ResponseError.TypeName)
+		}
+	}
+
+	if options.Namespaces {
+		declared := map[string]bool{}
+		for _, name := range modelTypeNames(model) {
+			declared[name] = true
+		}
+		client.lines = append(append(client.lines, ""), p.printNamespaces(model, declared)...)
+
+		// The aliases reference every component and operation type
+		client.useNames(namedTypeNames(model.Components)...)
+		client.useNames(operationTypeNames(model.Operations)...)
+	}
+	modules[clientModule] = client
+
+	extension := ".d.ts"
+	if options.EmitTs {
+		extension = ".ts"
+	}
+
+	files := p.linkModules(modules, extension)
+	g.declaredIn = map[string]string{}
+	for typeName, name := range declaredIn(modules) {
+		g.declaredIn[typeName] = name + extension
+	}
+	return files, g.errors.orNil()
+}
+
+// The module declaring each type name
+func declaredIn(modules map[string]*module) map[string]string {
+	declaredIn := map[string]string{}
+	for name, module := range modules {
+		for _, typeName := range module.declares {
+			declaredIn[typeName] = name
+		}
+	}
+	return declaredIn
+}
+
+// Add the imports each module needs, i.e. import type { ComponentSchemaPet } from "../components", including the
+// external ones
+func (p *tsPrinter) linkModules(modules map[string]*module, extension string) map[string]string {
+	declaredIn := declaredIn(modules)
+
+	files := map[string]string{}
+	for _, name := range sortedMapKeys(modules) {
+		module := modules[name]
+
+		// Type names this module uses from each other module, and from external modules
+		imports := map[Import]bool{}
+		for typeName := range module.references {
+			from, ok := declaredIn[typeName]
+			if ok && from != name {
+				imports[Import{Name: typeName, From: relativeImport(name, from)}] = true
+			}
+		}
+		for i := range module.imports {
+			imports[Import{Name: i.Name, From: externalImport(name, i.From)}] = true
+		}
+
+		lines := generatedHeader()
+		lines = append(lines, p.printImports(sortedImports(imports))...)
+		lines = append(lines, module.lines...)
+		files[name+extension] = strings.Join(lines, "\n")
+	}
+
+	return files
+}
+
+// An external import relative to the output directory, relative to module instead, i.e. (operations/pet, ./brands) -> ../brands
+func externalImport(module, from string) string {
+	if !strings.HasPrefix(from, ".") {
+		return from
+	}
+	return relativeImport(module, path.Clean(from))
+}
+
+// i.e. (operations/pet, components) -> ../components
+func relativeImport(from, to string) string {
+	fromDir := strings.Split(path.Dir(from), "/")
+	if fromDir[0] == "." {
+		fromDir = nil
+	}
+
+	prefix := "./"
+	if len(fromDir) > 0 {
+		prefix = strings.Repeat("../", len(fromDir))
+	} else if strings.HasPrefix(to, "../") {
+		prefix = ""
+	}
+	return prefix + to
+}
+
+// i.e. "Pet Store" -> pet-store
+func tagModuleName(tag string) string {
+	name := strings.Trim(tagModuleRegexp.ReplaceAllString(strings.ToLower(tag), "-"), "-")
+	if name == "" {
+		return "tag"
+	}
+	if name == untaggedModule {
+		return "tag-" + name
+	}
+	return name
+}
+
+// Whether name could be a module of GenerateTypedFetchFiles, i.e. index.d.ts or operations/pet.ts
+func IsModuleFile(name string) bool {
+	module := strings.TrimSuffix(strings.TrimSuffix(name, ".ts"), ".d")
+	if module == name {
+		return false
+	}
+
+	switch module {
+	case sharedModule, componentsModule, clientModule:
+		return true
+	}
+	dir, file := path.Split(module)
+	return dir == "operations/" && file != ""
+}
@@ -0,0 +1,93 @@
+package typedfetch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
+)
+
+func TestIsModuleFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"index.d.ts", true},
+		{"components.ts", true},
+		{"shared.d.ts", true},
+		{"operations/pet.d.ts", true},
+		{"operations/untagged.ts", true},
+		{"petstore.d.ts", false},
+		{"index.js", false},
+		{"legacy/index.d.ts", false},
+		{"operations/nested/pet.d.ts", false},
+		{"operations/.d.ts", false},
+	}
+
+	for _, test := range tests {
+		if got := IsModuleFile(test.name); got != test.want {
+			t.Errorf("IsModuleFile(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGenerateFilesImports(t *testing.T) {
+	spec := `
+openapi: 3.1.0
+info: {title: test, version: '1'}
+paths:
+  /pets:
+    post:
+      tags: [pets]
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object, properties: {id: {type: string, format: uuid, description: Not a ComponentSchemaOwner}}}
+      responses:
+        '200': {description: ok, content: {application/json: {schema: {$ref: '#/components/schemas/Pet'}}}}
+components:
+  schemas:
+    Owner: {type: string, enum: [ComponentSchemaPet]}
+    Pet: {type: object, properties: {owner: {$ref: '#/components/schemas/Owner'}, id: {type: string, format: uuid}}}
+`
+
+	reflector, err := loader.LoadBytes([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := GenerateTypedFetchFiles(reflector, Options{Mappings: []Mapping{{Format: "uuid", Type: "Uuid", Import: "./brands"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		// Neither the enum value nor the description is a reference
+		{"components.d.ts", []string{"import type { Uuid } from './brands';"}},
+		{"operations/pets.d.ts", []string{
+			"import type { Uuid } from '../brands';",
+			"import type { ComponentSchemaPet } from '../components';",
+			"import type { RequestInitExtended } from '../shared';",
+		}},
+		{"index.d.ts", []string{"import type { RequestPostPets, ResponseDataPostPets, ResponseErrorPostPets } from './operations/pets';"}},
+		{"shared.d.ts", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			imports := []string{}
+			for _, line := range strings.Split(files[test.file], "\n") {
+				if strings.HasPrefix(line, "import ") {
+					imports = append(imports, line)
+				}
+			}
+
+			if strings.Join(imports, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("imports = %q, want %q", imports, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package typedfetch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// A small TypeScript AST of the generated type expressions, printed by tsPrinter
+
+type tsType interface {
+	isTsType()
+}
+
+type tsKeyword struct {
+	keyword string
+}
+
+type tsReference struct {
+	name string
+	args []tsType
+}
+
+type tsStringLiteral struct {
+	value string
+}
+
+// Number or boolean literal
+type tsLiteral struct {
+	text string
+}
+
+type tsArray struct {
+	elem tsType
+}
+
+type tsUnion struct {
+	members []tsType
+}
+
+type tsIntersection struct {
+	members []tsType
+}
+
+type tsObject struct {
+	members []*tsMember
+
+	// Print on one line, i.e. { params?: ParamGetPet; }, if it fits and has no comments
+	inline bool
+}
+
+type tsMember struct {
+	// /** doc */ comment
+	doc string
+
+	// // comments, separated from the previous member by a blank line
+	comments []string
+
+	// An index signature, [key: string]: T, rather than a property
+	index bool
+
+	// Type of the index signature's key; nil for string
+	key tsType
+
+	readonly bool
+	name     string
+	optional bool
+	typ      tsType
+}
+
+// `prefix${string}`
+type tsTemplateLiteral struct {
+	prefix string
+}
+
+// TypeScript given verbatim
+type tsRaw struct {
+	text string
+}
+
+// /** comment */ T
+type tsCommented struct {
+	comment string
+	typ     tsType
+}
+
+func (*tsKeyword) isTsType()         {}
+func (*tsReference) isTsType()       {}
+func (*tsStringLiteral) isTsType()   {}
+func (*tsLiteral) isTsType()         {}
+func (*tsArray) isTsType()           {}
+func (*tsUnion) isTsType()           {}
+func (*tsIntersection) isTsType()    {}
+func (*tsObject) isTsType()          {}
+func (*tsTemplateLiteral) isTsType() {}
+func (*tsRaw) isTsType()             {}
+func (*tsCommented) isTsType()       {}
+
+var identifierNameRegexp = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+var templateLiteralReplacer = strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${")
+
+type tsPrinter struct {
+	format     Format
+	indentUnit string
+
+	// Printing a .ts module, which can have values, rather than .d.ts declarations
+	emitTs bool
+
+	// Export every declaration, not just Client
+	exportTypes bool
+}
+
+func newTsPrinter(format Format, emitTs bool) *tsPrinter {
+	format = format.withDefaults()
+
+	indentUnit := strings.Repeat(" ", format.Indent)
+	if format.UseTabs != nil && *format.UseTabs {
+		indentUnit = "\t"
+	}
+
+	return &tsPrinter{format: format, indentUnit: indentUnit, emitTs: emitTs}
+}
+
+// The printer of the modules of GenerateTypedFetchFiles, which import each other's types
+func (p *tsPrinter) exporting() *tsPrinter {
+	exporting := *p
+	exporting.exportTypes = true
+	return &exporting
+}
+
+// "export " if declarations are exported
+func (p *tsPrinter) export() string {
+	if p.exportTypes {
+		return "export "
+	}
+	return ""
+}
+
+func (p *tsPrinter) indent(depth int) string {
+	return strings.Repeat(p.indentUnit, depth)
+}
+
+func (p *tsPrinter) quote(s string) string {
+	q := "'"
+	if p.format.Quote == QuoteDouble {
+		q = `"`
+	}
+
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, q, `\`+q)
+	return q + s + q
+}
+
+// Quote a property name unless it's a valid identifier, i.e. x-rate-limit -> 'x-rate-limit'
+func (p *tsPrinter) propertyName(name string) string {
+	if identifierNameRegexp.MatchString(name) {
+		return name
+	}
+	return p.quote(name)
+}
+
+// type Name = T; preceded by its doc comment, if any
+func (p *tsPrinter) typeAlias(doc, name string, t tsType) []string {
+	lines := []string{}
+	if doc != "" {
+		lines = append(lines, doc)
+	}
+
+	prefix := fmt.Sprintf("%stype %s = ", p.export(), name)
+	return append(lines, joinBroken(prefix, p.print(t, 0, len(prefix), true))+";")
+}
+
+// import type { A, B } from 'module'; for each module, in order, followed by an empty line if there are any
+func (p *tsPrinter) printImports(imports []Import) []string {
+	lines := []string{}
+	for i := 0; i < len(imports); {
+		names := []string{}
+		from := imports[i].From
+		for ; i < len(imports) && imports[i].From == from; i++ {
+			names = append(names, imports[i].Name)
+		}
+		lines = append(lines, fmt.Sprintf("import type { %s } from %s;", strings.Join(names, ", "), p.quote(from)))
+	}
+
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// Print t starting at column of a line indented depth levels. A breakable union that doesn't fit within the line
+// width is printed as one member per line, starting with a newline.
+func (p *tsPrinter) print(t tsType, depth, column int, breakable bool) string {
+	switch t := t.(type) {
+	case *tsKeyword:
+		return t.keyword
+	case *tsReference:
+		if len(t.args) == 0 {
+			return t.name
+		}
+
+		args := []string{}
+		for _, arg := range t.args {
+			args = append(args, p.print(arg, depth, column, false))
+		}
+		return fmt.Sprintf("%s<%s>", t.name, strings.Join(args, ", "))
+	case *tsStringLiteral:
+		return p.quote(t.value)
+	case *tsLiteral:
+		return t.text
+	case *tsArray:
+		elem := p.print(t.elem, depth, column, false)
+		if p.needsParens(t.elem, "|&") {
+			elem = fmt.Sprintf("(%s)", elem)
+		}
+		return elem + "[]"
+	case *tsUnion:
+		return p.printUnion(t, depth, column, breakable)
+	case *tsIntersection:
+		members := []string{}
+		for _, member := range t.members {
+			printed := p.print(member, depth, column, false)
+			if p.needsParens(member, "|") {
+				printed = fmt.Sprintf("(%s)", printed)
+			}
+			members = append(members, printed)
+		}
+		return strings.Join(members, " & ")
+	case *tsObject:
+		return p.printObject(t, depth, column)
+	case *tsTemplateLiteral:
+		return "`" + templateLiteralReplacer.Replace(t.prefix) + "${string}`"
+	case *tsRaw:
+		return t.text
+	case *tsCommented:
+		return fmt.Sprintf("/** %s */ %s", escapeComment(t.comment), p.print(t.typ, depth, column, false))
+	}
+
+	panic(fmt.Sprintf("unknown TypeScript type %T", t))
+}
+
+func (p *tsPrinter) printUnion(union *tsUnion, depth, column int, breakable bool) string {
+	if len(union.members) == 0 {
+		return "never"
+	}
+
+	members := []string{}
+	for _, member := range union.members {
+		members = append(members, p.print(member, depth, column, false))
+	}
+
+	oneLine := strings.Join(members, " | ")
+	firstLine, _, _ := strings.Cut(oneLine, "\n")
+	if !breakable || len(members) < 2 || p.format.LineWidth < 0 || column+len(firstLine) <= p.format.LineWidth {
+		return oneLine
+	}
+
+	broken := ""
+	for _, member := range union.members {
+		prefix := p.indent(depth+1) + "| "
+		broken += "\n" + prefix + p.print(member, depth+1, len(prefix), false)
+	}
+	return broken
+}
+
+func (p *tsPrinter) printObject(object *tsObject, depth, column int) string {
+	if len(object.members) == 0 {
+		return "{}"
+	}
+
+	if object.inline {
+		members := []string{}
+		commented := false
+		for _, member := range object.members {
+			commented = commented || member.doc != "" || len(member.comments) > 0
+			members = append(members, p.printMember(member, depth)+";")
+		}
+
+		oneLine := fmt.Sprintf("{ %s }", strings.Join(members, " "))
+		fits := p.format.LineWidth < 0 || column+len(oneLine) <= p.format.LineWidth
+		if !commented && fits && !strings.Contains(oneLine, "\n") {
+			return oneLine
+		}
+	}
+
+	lines := []string{"{"}
+	for i, member := range object.members {
+		if len(member.comments) > 0 && i > 0 {
+			lines = append(lines, "")
+		}
+		for _, comment := range member.comments {
+			lines = append(lines, p.indent(depth+1)+"// "+comment)
+		}
+		if member.doc != "" {
+			lines = append(lines, p.indent(depth+1)+member.doc)
+		}
+		lines = append(lines, p.indent(depth+1)+p.printMember(member, depth+1)+";")
+	}
+	lines = append(lines, p.indent(depth)+"}")
+
+	return strings.Join(lines, "\n")
+}
+
+// name?: T, without the semicolon
+func (p *tsPrinter) printMember(member *tsMember, depth int) string {
+	name := p.propertyName(member.name)
+	if member.index {
+		key := "string"
+		if member.key != nil {
+			key = p.print(member.key, depth, 0, false)
+		}
+		name = fmt.Sprintf("[%s: %s]", member.name, key)
+	}
+
+	if member.readonly {
+		name = "readonly " + name
+	}
+
+	optional := ""
+	if member.optional {
+		optional = "?"
+	}
+
+	prefix := fmt.Sprintf("%s%s: ", name, optional)
+	return joinBroken(prefix, p.print(member.typ, depth, len(p.indent(depth))+len(prefix), true))
+}
+
+// Whether t must be parenthesized where any of the operators (| or &) would bind tighter than its own
+func (p *tsPrinter) needsParens(t tsType, operators string) bool {
+	switch t := t.(type) {
+	case *tsUnion:
+		if len(t.members) == 1 {
+			return p.needsParens(t.members[0], operators)
+		}
+		return len(t.members) > 1 && strings.Contains(operators, "|")
+	case *tsIntersection:
+		if len(t.members) == 1 {
+			// A union member is parenthesized already
+			return !p.needsParens(t.members[0], "|") && p.needsParens(t.members[0], operators)
+		}
+		return len(t.members) > 1 && strings.Contains(operators, "&")
+	case *tsRaw:
+		return parenthesizeType(t.text, operators) != t.text
+	case *tsCommented:
+		return p.needsParens(t.typ, operators)
+	}
+	return false
+}
+
+// Join a prefix like "name: " to a printed type that may start on the next line
+func joinBroken(prefix, printed string) string {
+	if strings.HasPrefix(printed, "\n") {
+		return strings.TrimRight(prefix, " ") + printed
+	}
+	return prefix + printed
+}
+
+// Replace each 4 space indentation level of a fixed block of TypeScript with the configured indentation
+func (p *tsPrinter) reindent(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		levels := (len(line) - len(trimmed)) / 4
+		lines[i] = p.indent(levels) + line[levels*4:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,127 @@
+package typedfetch
+
+import "testing"
+
+func TestTsPrinterPrint(t *testing.T) {
+	stringType := &tsKeyword{keyword: "string"}
+	numberType := &tsKeyword{keyword: "number"}
+	union := &tsUnion{members: []tsType{stringType, numberType}}
+	useTabs := true
+
+	tests := []struct {
+		name   string
+		format Format
+		typ    tsType
+		want   string
+	}{
+		{
+			name: "keyword",
+			typ:  stringType,
+			want: "string",
+		},
+		{
+			name: "reference with arguments",
+			typ:  &tsReference{name: "Record", args: []tsType{stringType, numberType}},
+			want: "Record<string, number>",
+		},
+		{
+			name: "string literal",
+			typ:  &tsStringLiteral{value: "it's"},
+			want: `'it\'s'`,
+		},
+		{
+			name:   "string literal with double quotes",
+			format: Format{Quote: "double"},
+			typ:    &tsStringLiteral{value: "dog"},
+			want:   `"dog"`,
+		},
+		{
+			name: "array of union",
+			typ:  &tsArray{elem: union},
+			want: "(string | number)[]",
+		},
+		{
+			name: "empty union",
+			typ:  &tsUnion{},
+			want: "never",
+		},
+		{
+			name: "intersection of union",
+			typ:  &tsIntersection{members: []tsType{union, &tsReference{name: "A"}}},
+			want: "(string | number) & A",
+		},
+		{
+			name: "template literal",
+			typ:  &tsTemplateLiteral{prefix: "x-`${"},
+			want: "`x-\\`\\${${string}`",
+		},
+		{
+			name: "commented",
+			typ:  &tsCommented{comment: "not */ closed", typ: &tsKeyword{keyword: "unknown"}},
+			want: "/** not * / closed */ unknown",
+		},
+		{
+			name: "empty object",
+			typ:  &tsObject{},
+			want: "{}",
+		},
+		{
+			name: "object",
+			typ: &tsObject{members: []*tsMember{
+				{name: "id", typ: numberType},
+				{name: "pet-name", optional: true, doc: "/** The name */", typ: stringType},
+				{index: true, name: "key", typ: &tsKeyword{keyword: "unknown"}},
+			}},
+			want: "{\n    id: number;\n    /** The name */\n    'pet-name'?: string;\n    [key: string]: unknown;\n}",
+		},
+		{
+			name:   "object with tabs",
+			format: Format{UseTabs: &useTabs},
+			typ:    &tsObject{members: []*tsMember{{readonly: true, name: "id", typ: numberType}}},
+			want:   "{\n\treadonly id: number;\n}",
+		},
+		{
+			name: "inline object",
+			typ:  &tsObject{inline: true, members: []*tsMember{{name: "params", optional: true, typ: &tsReference{name: "ParamGetPet"}}}},
+			want: "{ params?: ParamGetPet; }",
+		},
+		{
+			name: "inline object with a doc",
+			typ:  &tsObject{inline: true, members: []*tsMember{{name: "id", doc: "/** Id */", typ: numberType}}},
+			want: "{\n    /** Id */\n    id: number;\n}",
+		},
+		{
+			name: "template literal key",
+			typ:  &tsObject{members: []*tsMember{{index: true, name: "key", key: &tsTemplateLiteral{prefix: "x-"}, typ: stringType}}},
+			want: "{\n    [key: `x-${string}`]: string;\n}",
+		},
+		{
+			name:   "long union",
+			format: Format{LineWidth: 20},
+			typ: &tsObject{members: []*tsMember{{name: "status", typ: &tsUnion{members: []tsType{
+				&tsStringLiteral{value: "available"},
+				&tsStringLiteral{value: "pending"},
+			}}}}},
+			want: "{\n    status:\n        | 'available'\n        | 'pending';\n}",
+		},
+		{
+			name:   "long union without a line width",
+			format: Format{LineWidth: -1},
+			typ: &tsObject{members: []*tsMember{{name: "status", typ: &tsUnion{members: []tsType{
+				&tsStringLiteral{value: "available"},
+				&tsStringLiteral{value: "pending"},
+			}}}}},
+			want: "{\n    status: 'available' | 'pending';\n}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			printer := newTsPrinter(test.format, false)
+			got := printer.print(test.typ, 0, 0, true)
+			if got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
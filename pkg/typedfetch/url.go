@@ -3,6 +3,7 @@ package typedfetch
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/swaggest/openapi-go/openapi31"
 )
@@ -28,3 +29,8 @@ func getPathItemMethods(item *openapi31.PathItem) []OperationMethodTuple {
 func getUniqueEndpointName(method, path string) string {
 	return fmt.Sprintf("%s%s", pascalize(method), pathToVar(path))
 }
+
+// i.e. GET /pet/{petId} -> /paths/~1pet~1{petId}/get
+func getOperationPointer(method, path string) string {
+	return jsonPointer("paths", path, strings.ToLower(method))
+}
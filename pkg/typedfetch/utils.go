@@ -1,6 +1,7 @@
 package typedfetch
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 )
@@ -56,7 +57,7 @@ func itemInSlice[item comparable](slice []item, i item) bool {
 
 func isValidJsonType(t string) bool {
 	switch t {
-	case "string", "number", "integer", "boolean", "array", "object":
+	case "string", "number", "integer", "boolean", "array", "object", "null":
 		return true
 	default:
 		return false
@@ -71,3 +72,47 @@ func sortedMapKeys[T any](m map[string]T) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+func copySchema(schema map[string]any) map[string]any {
+	c := make(map[string]any, len(schema))
+	for k, v := range schema {
+		c[k] = v
+	}
+	return c
+}
+
+// Wrap a TypeScript type in parentheses if it contains any of the given operators (| or &) at the top level
+// i.e. "string | number" -> "(string | number)" so that it can be used as an array element type
+func parenthesizeType(tsType string, operators string) string {
+	depth := 0
+	var quote, prev rune
+	for _, c := range tsType {
+		previous := prev
+		prev = c
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '{', '(', '[', '<':
+			depth++
+		case '}', ')', ']':
+			depth--
+		case '>':
+			// skip the arrow of a function type
+			if previous != '=' {
+				depth--
+			}
+		default:
+			if depth == 0 && strings.ContainsRune(operators, c) {
+				return fmt.Sprintf("(%s)", tsType)
+			}
+		}
+	}
+	return tsType
+}
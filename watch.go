@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/RPGillespie6/typed-fetch/pkg/loader"
+)
+
+const (
+	// How often the sources of every generation are polled for changes
+	watchInterval = 250 * time.Millisecond
+
+	// How long the sources must stay unchanged before regenerating, so an editor saving
+	// several files (or one file in several writes) only triggers a single regeneration
+	watchDebounce = 300 * time.Millisecond
+)
+
+type watchedGeneration struct {
+	generation Generation
+
+	// The spec and every local file it references, as of the last successful load
+	sources []string
+	stamps  map[string]fileStamp
+
+	// Set when a change is seen, cleared once the generation is regenerated
+	changed   bool
+	changedAt time.Time
+}
+
+// Enough to tell whether a file changed without reading it; the zero value means the file doesn't exist
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Generate every generation, then poll their sources and regenerate whenever they change.
+// Errors are reported but never stop watching; only returns for invalid usage.
+func watch(generations []Generation) int {
+	watched := []*watchedGeneration{}
+	for _, generation := range generations {
+		if generation.OpenApi == "-" || loader.IsRemoteLocation(generation.OpenApi) {
+			fmt.Fprintf(os.Stderr, "error: %s: --watch requires a local spec file\n", generation.OpenApi)
+			return exitUsage
+		}
+
+		err := validateGeneration(generation, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			return exitUsage
+		}
+
+		watched = append(watched, &watchedGeneration{
+			generation: generation,
+			sources:    []string{generation.OpenApi},
+		})
+	}
+
+	for _, w := range watched {
+		w.regenerate()
+	}
+	fmt.Fprintln(os.Stderr, "watching for changes...")
+
+	for {
+		time.Sleep(watchInterval)
+
+		for _, w := range watched {
+			stamps := statSources(w.sources)
+			if !maps.Equal(stamps, w.stamps) {
+				w.stamps = stamps
+				w.changed = true
+				w.changedAt = time.Now()
+				continue
+			}
+
+			if w.changed && time.Since(w.changedAt) >= watchDebounce {
+				w.regenerate()
+			}
+		}
+	}
+}
+
+func (w *watchedGeneration) regenerate() {
+	w.changed = false
+	w.stamps = statSources(w.sources)
+
+	document, err := loader.Load(w.generation.OpenApi, w.generation.loaderOptions())
+	if err != nil {
+		// Keep watching the previous sources, so fixing a broken referenced file is picked up too
+		reportError(err, nil)
+		return
+	}
+
+	// References may have been added or removed. Files that were already watched keep their stamps from before the
+	// load, so that a save during the load is picked up by the next poll.
+	stamps := statSources(document.Sources)
+	for source := range stamps {
+		if stamp, ok := w.stamps[source]; ok {
+			stamps[source] = stamp
+		}
+	}
+	w.sources = document.Sources
+	w.stamps = stamps
+
+	exitCode := generateOutput(w.generation, document, false)
+	output := w.generation.Output + w.generation.OutputDir
+	if exitCode == exitOk && output != "" {
+		fmt.Fprintf(os.Stderr, "%s: generated %s\n", time.Now().Format(time.TimeOnly), output)
+	}
+}
+
+func statSources(sources []string) map[string]fileStamp {
+	stamps := map[string]fileStamp{}
+	for _, source := range sources {
+		info, err := os.Stat(source)
+		if err != nil {
+			stamps[source] = fileStamp{}
+			continue
+		}
+
+		stamps[source] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps
+}